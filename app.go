@@ -2,10 +2,10 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,25 +17,83 @@ import (
 
 // App struct
 type App struct {
-	ctx context.Context
-	db  *sql.DB
+	ctx         context.Context
+	db          *sql.DB
+	resolver    Resolver       // DNS解析器，nil时使用系统解析器
+	dnsResolver RecordResolver // CAA、TLSA等DNS记录查询使用的解析器，nil时直接查询系统DNS服务器
+	httpClient  *http.Client   // OCSP等吊销查询使用的HTTP客户端，nil时使用默认客户端
+
+	settingsMu     sync.RWMutex
+	settings       Settings // 探测设置（超时、重试、全局代理）
+	settingsLoaded bool
+
+	probeMu      sync.Mutex
+	probeCtx     context.Context // 探测使用的上下文，取消后所有进行中的探测立即结束
+	cancelProbes context.CancelFunc
+
+	schemaStatus SchemaStatus // 数据库初始化和迁移状态
+
+	pruneMu     sync.Mutex   // 定期清理和手动清理不同时进行
+	lastPruneMu sync.Mutex   // 保护 lastPrune，清理（包括VACUUM）期间也能读取
+	lastPrune   *PruneReport // 最近一次历史记录清理的结果
 }
 
 // CertificateInfo 证书信息结构
 type CertificateInfo struct {
 	ID            int64    `json:"id,omitempty"`
 	Domain        string   `json:"domain"`
+	Port          int      `json:"port,omitempty"`     // 端口（默认443）
+	Protocol      string   `json:"protocol,omitempty"` // STARTTLS协议（空表示直接TLS）
 	Issuer        string   `json:"issuer"`
 	Subject       string   `json:"subject"`
 	NotBefore     string   `json:"notBefore"`
 	NotAfter      string   `json:"notAfter"`
 	DaysRemaining int      `json:"daysRemaining"`
 	IsValid       bool     `json:"isValid"`
-	Status        string   `json:"status"` // "safe", "warning", "danger", "expired"
+	Status        string   `json:"status"` // "safe", "warning", "danger", "expired", "untrusted", "mismatch", "revoked"
 	SerialNumber  string   `json:"serialNumber"`
 	Version       int      `json:"version"`
-	QueryTime     string   `json:"queryTime,omitempty"`  // 查询时间
-	SANDomains    []string `json:"sanDomains,omitempty"` // SAN域名列表（Subject Alternative Names）
+	QueryTime     string   `json:"queryTime,omitempty"`    // 查询时间
+	SANDomains    []string `json:"sanDomains,omitempty"`   // SAN域名列表（Subject Alternative Names）
+	SANIPs        []string `json:"sanIps,omitempty"`       // IP类型的SAN
+	ChainValid    bool     `json:"chainValid"`             // 证书链是否可信
+	HostnameMatch bool     `json:"hostnameMatch"`          // 证书是否匹配主机名
+	VerifyErrors  []string `json:"verifyErrors,omitempty"` // 校验错误列表
+
+	PublicKeyAlgorithm string   `json:"publicKeyAlgorithm,omitempty"` // 公钥算法，如 "RSA"、"ECDSA"
+	PublicKeySize      int      `json:"publicKeySize,omitempty"`      // 公钥长度（位）
+	SignatureAlgorithm string   `json:"signatureAlgorithm,omitempty"` // 签名算法，如 "SHA256-RSA"
+	FingerprintSHA1    string   `json:"fingerprintSha1,omitempty"`    // SHA-1指纹
+	FingerprintSHA256  string   `json:"fingerprintSha256,omitempty"`  // SHA-256指纹
+	PublicKeySHA256    string   `json:"publicKeySha256,omitempty"`    // 公钥（SPKI）的SHA-256指纹，用于判断是否更换了密钥
+	KeyUsage           []string `json:"keyUsage,omitempty"`           // 密钥用法
+	ExtKeyUsage        []string `json:"extKeyUsage,omitempty"`        // 扩展密钥用法
+	BasicConstraints   string   `json:"basicConstraints,omitempty"`   // 基本约束，如 "CA:FALSE"
+	WeakFlags          []string `json:"weakFlags,omitempty"`          // 弱配置（短RSA密钥、SHA-1签名、缺少serverAuth等）
+
+	Chain              []ChainCertificate `json:"chain,omitempty"`              // 服务器发送的完整证书链
+	ChainIssues        []string           `json:"chainIssues,omitempty"`        // 证书链问题
+	ChainNotAfter      string             `json:"chainNotAfter,omitempty"`      // 链中（叶子和中间证书）最早的过期时间
+	ChainDaysRemaining int                `json:"chainDaysRemaining,omitempty"` // 链中最早过期证书的剩余天数
+	PEM                string             `json:"pem,omitempty"`                // 服务器发送的证书链（PEM格式，叶子证书在前）
+
+	ConnectAddress string          `json:"connectAddress,omitempty"` // 指定的连接地址（为空表示连接目标本身）
+	SNI            string          `json:"sni,omitempty"`            // 握手时发送的SNI（为空表示未发送）
+	ConnectedIP    string          `json:"connectedIp,omitempty"`    // 实际连接的IP
+	TLSVersion     string          `json:"tlsVersion,omitempty"`     // 协商的TLS版本
+	DurationMs     int64           `json:"durationMs,omitempty"`     // 探测耗时（毫秒）
+	IPResults      []IPProbeResult `json:"ipResults,omitempty"`      // 逐IP探测结果
+	IPInconsistent bool            `json:"ipInconsistent,omitempty"` // 各节点证书是否不一致
+	Warnings       []string        `json:"warnings,omitempty"`       // 警告信息
+
+	OCSP *OCSPInfo  `json:"ocsp,omitempty"` // OCSP吊销状态
+	CRL  []CRLCheck `json:"crl,omitempty"`  // CRL检查结果
+
+	TLSScan *TLSScanResult `json:"tlsScan,omitempty"` // 深度扫描结果（协议版本、加密套件、评级）
+	CT      *CTInfo        `json:"ct,omitempty"`      // 证书透明度（SCT）检查结果
+	CAA     *CAAInfo       `json:"caa,omitempty"`     // DNS CAA检查结果
+	DANE    *DANEInfo      `json:"dane,omitempty"`    // DANE/TLSA校验结果
+	HTTP    *HTTPInfo      `json:"http,omitempty"`    // HTTP层检查结果（HSTS、重定向）
 }
 
 // QueryResult 查询结果
@@ -68,6 +126,15 @@ type HistoryQueryResult struct {
 type WatchedDomain struct {
 	ID               int64            `json:"id"`
 	Domain           string           `json:"domain"`
+	Port             int              `json:"port"`                    // 端口（默认443）
+	Protocol         string           `json:"protocol,omitempty"`      // STARTTLS协议（空表示直接TLS）
+	TrustStoreID     int64            `json:"trustStoreId"`            // 指定的信任根（0表示仅系统根证书和全局信任根）
+	Options          ProbeOptions     `json:"options"`                 // 探测选项
+	TLSGrade         string           `json:"tlsGrade,omitempty"`      // 最近一次深度扫描的评级
+	CAAWarning       string           `json:"caaWarning,omitempty"`    // CAA记录不允许当前颁发者时的警告
+	HSTSWarning      string           `json:"hstsWarning,omitempty"`   // 设置了未启用HSTS警告且未启用HSTS时的警告
+	UnicodeDomain    string           `json:"unicodeDomain,omitempty"` // 国际化域名的Unicode形式（domain 为punycode）
+	Changes          []CertEvent      `json:"changes,omitempty"`       // 本次刷新检测到的证书变更
 	Nickname         string           `json:"nickname,omitempty"`
 	AddedTime        string           `json:"addedTime"`
 	LastCheckTime    string           `json:"lastCheckTime,omitempty"`
@@ -77,6 +144,8 @@ type WatchedDomain struct {
 	IsManual         bool             `json:"isManual"`                   // 是否手动录入
 	ManualExpireDate string           `json:"manualExpireDate,omitempty"` // 手动录入的过期时间
 	ManualStartDate  string           `json:"manualStartDate,omitempty"`  // 手动录入的生效时间
+
+	proxyErr error // 保存的代理无法解密时的错误，探测时返回
 }
 
 // WatchedDomainsResult 关注域名查询结果
@@ -105,11 +174,20 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	// 初始化数据库
 	a.initDB()
+	// 按保留策略定期清理历史记录
+	if a.db != nil {
+		go a.runRetention()
+	}
 }
 
 // CheckCertificate 检查SSL证书（用户主动查询，保存历史记录）
 func (a *App) CheckCertificate(domain string) QueryResult {
-	result := a.checkCertificateInternal(domain)
+	return a.CheckCertificateWithOptions(domain, ProbeOptions{})
+}
+
+// CheckCertificateWithOptions 按指定选项检查SSL证书（用户主动查询，保存历史记录）
+func (a *App) CheckCertificateWithOptions(domain string, opts ProbeOptions) QueryResult {
+	result := a.checkCertificateInternal(domain, opts)
 
 	// 用户主动查询时保存到历史记录
 	if result.Success {
@@ -129,127 +207,86 @@ func (a *App) CheckCertificate(domain string) QueryResult {
 }
 
 // checkCertificateInternal 内部证书查询方法（不保存历史记录）
-func (a *App) checkCertificateInternal(domain string) QueryResult {
-	if domain == "" {
+func (a *App) checkCertificateInternal(domain string, opts ProbeOptions) QueryResult {
+	// 解析目标（支持 host:port、URL 及 IPv6 格式）
+	target, err := parseTarget(domain)
+	if err != nil {
 		return QueryResult{
 			Success: false,
-			Error:   "请输入有效的域名",
-			Message: "域名不能为空",
+			Error:   err.Error(),
+			Message: fmt.Sprintf("无效的查询目标：%v", err),
 		}
 	}
 
-	// 连接超时设置为5秒
-	dialer := &net.Dialer{
-		Timeout: 5 * time.Second,
+	// 全局信任根对所有查询生效
+	opts, err = opts.normalize()
+	if err == nil {
+		opts.Roots, err = a.trustRoots(0)
+	}
+	if err == nil {
+		opts.ClientCert, err = a.clientCertificate(opts.ClientCertID)
 	}
-
-	// 建立TLS连接
-	conn, err := tls.DialWithDialer(dialer, "tcp", domain+":443", &tls.Config{
-		InsecureSkipVerify: true, // 跳过证书验证，因为我们只关心获取证书信息
-	})
-
 	if err != nil {
 		return QueryResult{
 			Success: false,
 			Error:   err.Error(),
-			Message: fmt.Sprintf("无法连接到 %s：%v", domain, err),
+			Message: err.Error(),
 		}
 	}
-	defer conn.Close()
-
-	// 获取证书链
-	certs := conn.ConnectionState().PeerCertificates
-	if len(certs) == 0 {
-		return QueryResult{
-			Success: false,
-			Error:   "未获取到证书信息",
-			Message: "服务器未返回证书",
-		}
-	}
-
-	// 获取第一个证书（服务器证书）
-	cert := certs[0]
-
-	// 获取SAN域名列表
-	var sanDomains []string
-	if len(cert.DNSNames) > 0 {
-		sanDomains = cert.DNSNames
-	}
 
-	// 计算过期时间
-	expiryDate := cert.NotAfter
-	startDate := cert.NotBefore
-	daysRemaining := int(expiryDate.Sub(time.Now()).Hours() / 24)
+	return a.probeTarget(a.probeContext(), target, opts)
+}
 
-	// 判断证书状态
-	var status string
-	if daysRemaining < 0 {
-		status = "expired"
-	} else if daysRemaining <= 7 {
-		status = "danger"
-	} else if daysRemaining <= 30 {
-		status = "warning"
-	} else {
-		status = "safe"
+// appDataPath 返回应用数据目录（不创建）
+// Windows: C:\Users\用户名\AppData\Roaming\SSL-Cert-Checker
+func appDataPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("获取应用数据目录失败: %v", err)
 	}
+	return filepath.Join(configDir, "SSL-Cert-Checker"), nil
+}
 
-	// 构建证书信息
-	certInfo := &CertificateInfo{
-		Domain:        domain,
-		Issuer:        cert.Issuer.CommonName,
-		Subject:       cert.Subject.CommonName,
-		NotBefore:     startDate.Format("2006-01-02 15:04:05"),
-		NotAfter:      expiryDate.Format("2006-01-02 15:04:05"),
-		DaysRemaining: daysRemaining,
-		IsValid:       daysRemaining > 0,
-		Status:        status,
-		SerialNumber:  cert.SerialNumber.String(),
-		Version:       cert.Version,
-		SANDomains:    sanDomains,
+// appDataDir 返回并创建应用数据目录
+func appDataDir() (string, error) {
+	dir, err := appDataPath()
+	if err != nil {
+		return "", err
 	}
 
-	return QueryResult{
-		Success: true,
-		Message: "证书查询成功",
-		Data:    certInfo,
+	// 创建应用专属目录
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建数据目录失败: %v", err)
 	}
+	return dir, nil
 }
 
 // initDB 初始化SQLite数据库
 func (a *App) initDB() {
 	var err error
 
-	// 获取应用数据目录
-	// Windows: C:\Users\用户名\AppData\Roaming\SSL-Cert-Checker
-	appDataDir, err := os.UserConfigDir()
-	if err != nil {
-		fmt.Printf("获取应用数据目录失败: %v\n", err)
-		return
-	}
-
-	// 创建应用专属目录
-	dbDir := filepath.Join(appDataDir, "SSL-Cert-Checker")
-	err = os.MkdirAll(dbDir, 0755)
+	dbDir, err := appDataDir()
 	if err != nil {
-		fmt.Printf("创建数据目录失败: %v\n", err)
+		a.dbFailed(err)
 		return
 	}
 
 	// 数据库文件路径
 	dbPath := filepath.Join(dbDir, "data.db")
+	a.schemaStatus.DBPath = dbPath
 	fmt.Printf("数据库文件路径: %s\n", dbPath)
 
 	// 连接SQLite数据库
 	a.db, err = sql.Open("sqlite", dbPath)
 	if err != nil {
-		fmt.Printf("连接SQLite数据库失败: %v\n", err)
+		a.dbFailed(fmt.Errorf("连接SQLite数据库失败: %v", err))
 		return
 	}
 
 	// 测试数据库连接
 	err = a.db.Ping()
 	if err != nil {
-		fmt.Printf("数据库连接测试失败: %v\n", err)
+		a.dbFailed(fmt.Errorf("数据库连接测试失败: %v", err))
 		return
 	}
 
@@ -258,70 +295,32 @@ func (a *App) initDB() {
 	a.db.SetMaxIdleConns(1)
 	a.db.SetConnMaxLifetime(0)
 
-	// 创建表结构
-	err = a.createTables()
+	// 执行数据库迁移
+	version, backupPath, err := a.migrate(dbPath)
+	a.schemaStatus.Version = version
+	a.schemaStatus.BackupPath = backupPath
 	if err != nil {
-		fmt.Printf("创建数据表失败: %v\n", err)
+		if backupPath != "" {
+			err = fmt.Errorf("%v（迁移前的数据已备份到 %s）", err, backupPath)
+		}
+		a.dbFailed(fmt.Errorf("数据库迁移失败: %v", err))
 		return
 	}
 
+	// 加载探测设置
+	a.loadSettings()
+
 	fmt.Println("✅ SQLite数据库连接成功")
 }
 
-// createTables 创建数据表
-func (a *App) createTables() error {
-	// 创建证书历史记录表
-	certificatesTable := `
-	CREATE TABLE IF NOT EXISTS certificates (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		domain TEXT NOT NULL,
-		issuer TEXT,
-		subject TEXT,
-		not_before DATETIME,
-		not_after DATETIME,
-		days_remaining INTEGER,
-		is_valid BOOLEAN,
-		status TEXT,
-		serial_number TEXT,
-		version INTEGER,
-		query_time DATETIME DEFAULT (datetime('now', 'localtime'))
-	);
-	`
-
-	_, err := a.db.Exec(certificatesTable)
-	if err != nil {
-		return fmt.Errorf("创建certificates表失败: %v", err)
-	}
-
-	// 创建关注域名表
-	watchedDomainsTable := `
-	CREATE TABLE IF NOT EXISTS watched_domains (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		domain TEXT NOT NULL UNIQUE,
-		nickname TEXT,
-		added_time DATETIME DEFAULT (datetime('now', 'localtime')),
-		last_check_time DATETIME,
-		notify_enabled BOOLEAN DEFAULT 0,
-		notify_threshold INTEGER DEFAULT 7,
-		is_manual BOOLEAN DEFAULT 0,
-		manual_expire_date DATETIME,
-		manual_start_date DATETIME
-	);
-	`
-
-	_, err = a.db.Exec(watchedDomainsTable)
-	if err != nil {
-		return fmt.Errorf("创建watched_domains表失败: %v", err)
+// dbFailed 记录数据库初始化失败的原因并关闭连接，界面启动时通过 GetSchemaStatus 提示用户
+func (a *App) dbFailed(err error) {
+	fmt.Printf("❌ %v\n", err)
+	a.schemaStatus.Error = err.Error()
+	if a.db != nil {
+		a.db.Close()
+		a.db = nil
 	}
-
-	// 为旧数据添加新字段（如果不存在）
-	a.db.Exec("ALTER TABLE watched_domains ADD COLUMN notify_enabled BOOLEAN DEFAULT 0")
-	a.db.Exec("ALTER TABLE watched_domains ADD COLUMN notify_threshold INTEGER DEFAULT 7")
-	a.db.Exec("ALTER TABLE watched_domains ADD COLUMN is_manual BOOLEAN DEFAULT 0")
-	a.db.Exec("ALTER TABLE watched_domains ADD COLUMN manual_expire_date DATETIME")
-	a.db.Exec("ALTER TABLE watched_domains ADD COLUMN manual_start_date DATETIME")
-
-	return nil
 }
 
 // saveCertificate 保存证书信息到数据库
@@ -330,16 +329,38 @@ func (a *App) saveCertificate(cert *CertificateInfo) error {
 		return fmt.Errorf("数据库未初始化")
 	}
 
+	// 深度扫描结果以JSON保存在历史记录旁
+	tlsGrade := ""
+	var tlsScan interface{}
+	if cert.TLSScan != nil {
+		tlsGrade = cert.TLSScan.Grade
+		if data, err := json.Marshal(cert.TLSScan); err == nil {
+			tlsScan = string(data)
+		}
+	}
+
+	// OCSP、CT、HTTP等嵌套的检查结果以JSON保存
+	var details interface{}
+	if data, err := json.Marshal(newHistoryDetails(cert)); err == nil {
+		details = string(data)
+	}
+
 	// SQLite可以直接存储字符串格式的日期时间
 	insertSQL := `
 	INSERT INTO certificates (
-		domain, issuer, subject, not_before, not_after, 
-		days_remaining, is_valid, status, serial_number, version
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		domain, port, protocol, issuer, subject, not_before, not_after, 
+		days_remaining, is_valid, status, serial_number, version,
+		chain_valid, hostname_match, verify_errors, connect_address, sni,
+		tls_grade, tls_scan, key_algorithm, key_size, signature_algorithm,
+		fingerprint_sha1, fingerprint_sha256, key_usage, ext_key_usage, basic_constraints,
+		weak_flags, is_weak, pem, san_domains, san_ips, connected_ip, tls_version, duration_ms, details
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := a.db.Exec(insertSQL,
 		cert.Domain,
+		cert.Port,
+		cert.Protocol,
 		cert.Issuer,
 		cert.Subject,
 		cert.NotBefore,
@@ -349,11 +370,36 @@ func (a *App) saveCertificate(cert *CertificateInfo) error {
 		cert.Status,
 		cert.SerialNumber,
 		cert.Version,
+		cert.ChainValid,
+		cert.HostnameMatch,
+		encodeStringList(cert.VerifyErrors),
+		cert.ConnectAddress,
+		cert.SNI,
+		tlsGrade,
+		tlsScan,
+		cert.PublicKeyAlgorithm,
+		cert.PublicKeySize,
+		cert.SignatureAlgorithm,
+		cert.FingerprintSHA1,
+		cert.FingerprintSHA256,
+		encodeStringList(cert.KeyUsage),
+		encodeStringList(cert.ExtKeyUsage),
+		cert.BasicConstraints,
+		encodeStringList(cert.WeakFlags),
+		len(cert.WeakFlags) > 0,
+		cert.PEM,
+		encodeStringList(cert.SANDomains),
+		encodeStringList(cert.SANIPs),
+		cert.ConnectedIP,
+		cert.TLSVersion,
+		cert.DurationMs,
+		details,
 	)
 
 	if err != nil {
 		return fmt.Errorf("插入数据失败: %v", err)
 	}
+	cert.ID, _ = result.LastInsertId()
 
 	// 记录日志（仅用于调试）
 	rowsAffected, _ := result.RowsAffected()
@@ -371,20 +417,38 @@ func (a *App) BatchCheckCertificates(domains string) BatchQueryResult {
 		}
 	}
 
-	// 按行分割域名
+	// 按行分割域名，先统一解析校验，规范化后相同的目标只查询一次
 	domainList := strings.Split(strings.TrimSpace(domains), "\n")
 	var validDomains []string
-	for _, domain := range domainList {
+	var errors []string
+	total, duplicates := 0, 0
+	seen := make(map[string]bool)
+	for i, domain := range domainList {
 		domain = strings.TrimSpace(domain)
-		if domain != "" {
-			validDomains = append(validDomains, domain)
+		if domain == "" {
+			continue
+		}
+		total++
+
+		target, err := parseTarget(domain)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("第 %d 行 %s: %v", i+1, domain, err))
+			continue
 		}
+		if seen[target.String()] {
+			duplicates++
+			continue
+		}
+		seen[target.String()] = true
+		validDomains = append(validDomains, target.String())
 	}
 
 	if len(validDomains) == 0 {
 		return BatchQueryResult{
 			Success: false,
 			Message: "没有有效的域名",
+			Total:   total,
+			Errors:  errors,
 		}
 	}
 
@@ -392,7 +456,6 @@ func (a *App) BatchCheckCertificates(domains string) BatchQueryResult {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var results []CertificateInfo
-	var errors []string
 
 	for _, domain := range validDomains {
 		wg.Add(1)
@@ -414,76 +477,103 @@ func (a *App) BatchCheckCertificates(domains string) BatchQueryResult {
 
 	wg.Wait()
 
+	message := fmt.Sprintf("共查询 %d 个域名，成功 %d 个", len(validDomains), len(results))
+	if invalid := total - duplicates - len(validDomains); invalid > 0 || duplicates > 0 {
+		message += fmt.Sprintf("（无效 %d 个，重复 %d 个）", invalid, duplicates)
+	}
+
 	return BatchQueryResult{
 		Success: len(results) > 0,
-		Message: fmt.Sprintf("共查询 %d 个域名，成功 %d 个", len(validDomains), len(results)),
-		Total:   len(validDomains),
+		Message: message,
+		Total:   total,
 		Results: results,
 		Errors:  errors,
 	}
 }
 
-// GetHistory 获取历史记录
-func (a *App) GetHistory(limit int) HistoryQueryResult {
-	if a.db == nil {
-		return HistoryQueryResult{
-			Success: false,
-			Error:   "数据库未初始化",
-		}
-	}
-
-	if limit <= 0 {
-		limit = 50 // 默认查询50条
-	}
-
-	querySQL := `
-	SELECT id, domain, issuer, subject, 
-	       strftime('%Y-%m-%d %H:%M:%S', not_before) as not_before,
-	       strftime('%Y-%m-%d %H:%M:%S', not_after) as not_after,
-	       days_remaining, is_valid, status, serial_number, version, 
-	       strftime('%Y-%m-%d %H:%M:%S', query_time) as query_time
-	FROM certificates
-	ORDER BY query_time DESC
-	LIMIT ?
-	`
-
-	rows, err := a.db.Query(querySQL, limit)
-	if err != nil {
-		return HistoryQueryResult{
-			Success: false,
-			Error:   fmt.Sprintf("查询失败: %v", err),
-		}
-	}
-	defer rows.Close()
-
-	var records []CertificateInfo
-	for rows.Next() {
-		var cert CertificateInfo
-		err := rows.Scan(
-			&cert.ID,
-			&cert.Domain,
-			&cert.Issuer,
-			&cert.Subject,
-			&cert.NotBefore,
-			&cert.NotAfter,
-			&cert.DaysRemaining,
-			&cert.IsValid,
-			&cert.Status,
-			&cert.SerialNumber,
-			&cert.Version,
-			&cert.QueryTime,
-		)
-		if err != nil {
-			continue
+// historyColumns 历史记录列表查询的列，与 scanHistoryRecord 的扫描顺序一致（不含证书原文和检查详情）
+const historyColumns = `
+	id, domain, COALESCE(port, 443), COALESCE(protocol, ''), issuer, subject, 
+	strftime('%Y-%m-%d %H:%M:%S', not_before) as not_before,
+	strftime('%Y-%m-%d %H:%M:%S', not_after) as not_after,
+	days_remaining, is_valid, status, serial_number, version, 
+	COALESCE(chain_valid, 1), COALESCE(hostname_match, 1), verify_errors,
+	COALESCE(connect_address, ''), COALESCE(sni, domain), tls_scan,
+	COALESCE(key_algorithm, ''), COALESCE(key_size, 0), COALESCE(signature_algorithm, ''),
+	COALESCE(fingerprint_sha1, ''), COALESCE(fingerprint_sha256, ''),
+	key_usage, ext_key_usage, COALESCE(basic_constraints, ''), weak_flags,
+	san_domains, san_ips, COALESCE(connected_ip, ''), COALESCE(tls_version, ''), COALESCE(duration_ms, 0),
+	strftime('%Y-%m-%d %H:%M:%S', query_time) as query_time
+`
+
+// scanHistoryRecord 扫描一行历史记录，extra 为 historyColumns 之后追加的列
+func scanHistoryRecord(row rowScanner, extra ...interface{}) (*CertificateInfo, error) {
+	var cert CertificateInfo
+	var verifyErrors, tlsScan, keyUsage, extKeyUsage, weak, sanDomains, sanIPs sql.NullString
+	dest := []interface{}{
+		&cert.ID,
+		&cert.Domain,
+		&cert.Port,
+		&cert.Protocol,
+		&cert.Issuer,
+		&cert.Subject,
+		&cert.NotBefore,
+		&cert.NotAfter,
+		&cert.DaysRemaining,
+		&cert.IsValid,
+		&cert.Status,
+		&cert.SerialNumber,
+		&cert.Version,
+		&cert.ChainValid,
+		&cert.HostnameMatch,
+		&verifyErrors,
+		&cert.ConnectAddress,
+		&cert.SNI,
+		&tlsScan,
+		&cert.PublicKeyAlgorithm,
+		&cert.PublicKeySize,
+		&cert.SignatureAlgorithm,
+		&cert.FingerprintSHA1,
+		&cert.FingerprintSHA256,
+		&keyUsage,
+		&extKeyUsage,
+		&cert.BasicConstraints,
+		&weak,
+		&sanDomains,
+		&sanIPs,
+		&cert.ConnectedIP,
+		&cert.TLSVersion,
+		&cert.DurationMs,
+		&cert.QueryTime,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	cert.VerifyErrors = decodeStringList(verifyErrors.String)
+	cert.KeyUsage = decodeStringList(keyUsage.String)
+	cert.ExtKeyUsage = decodeStringList(extKeyUsage.String)
+	cert.WeakFlags = decodeStringList(weak.String)
+	cert.SANDomains = decodeStringList(sanDomains.String)
+	cert.SANIPs = decodeStringList(sanIPs.String)
+	if tlsScan.Valid && tlsScan.String != "" {
+		var scan TLSScanResult
+		if json.Unmarshal([]byte(tlsScan.String), &scan) == nil {
+			cert.TLSScan = &scan
 		}
-		records = append(records, cert)
 	}
+	return &cert, nil
+}
 
+// GetHistory 获取最近的历史记录，等同于不带条件的 QueryHistory 第一页
+func (a *App) GetHistory(limit int) HistoryQueryResult {
+	page := a.QueryHistory(HistoryQuery{Limit: limit})
 	return HistoryQueryResult{
-		Success: true,
-		Message: fmt.Sprintf("查询到 %d 条历史记录", len(records)),
-		Total:   len(records),
-		Records: records,
+		Success: page.Success,
+		Message: page.Message,
+		Total:   len(page.Records),
+		Records: page.Records,
+		Error:   page.Error,
 	}
 }
 
@@ -508,16 +598,17 @@ func (a *App) AddWatchedDomain(domain, nickname string) QueryResult {
 		}
 	}
 
-	if domain == "" {
+	target, err := parseTarget(domain)
+	if err != nil {
 		return QueryResult{
 			Success: false,
-			Error:   "域名不能为空",
+			Error:   err.Error(),
 		}
 	}
 
-	// 检查是否已经关注
+	// 检查是否已经关注（同一主机的不同端口视为不同目标）
 	var count int
-	err := a.db.QueryRow("SELECT COUNT(*) FROM watched_domains WHERE domain = ?", domain).Scan(&count)
+	err = a.db.QueryRow("SELECT COUNT(*) FROM watched_domains WHERE domain = ? AND port = ?", target.Host, target.Port).Scan(&count)
 	if err != nil {
 		return QueryResult{
 			Success: false,
@@ -533,8 +624,8 @@ func (a *App) AddWatchedDomain(domain, nickname string) QueryResult {
 	}
 
 	// 插入关注域名
-	insertSQL := "INSERT INTO watched_domains (domain, nickname) VALUES (?, ?)"
-	_, err = a.db.Exec(insertSQL, domain, nickname)
+	insertSQL := "INSERT INTO watched_domains (domain, port, protocol, nickname) VALUES (?, ?, ?, ?)"
+	_, err = a.db.Exec(insertSQL, target.Host, target.Port, target.Protocol, nickname)
 	if err != nil {
 		return QueryResult{
 			Success: false,
@@ -548,6 +639,75 @@ func (a *App) AddWatchedDomain(domain, nickname string) QueryResult {
 	}
 }
 
+// watchedDomainColumns 查询关注域名时的列，与 scanWatchedDomain 的扫描顺序一致
+const watchedDomainColumns = `
+	id, domain, port, COALESCE(protocol, ''), nickname, 
+	strftime('%Y-%m-%d %H:%M:%S', added_time) as added_time,
+	strftime('%Y-%m-%d %H:%M:%S', last_check_time) as last_check_time,
+	notify_enabled, notify_threshold, is_manual,
+	strftime('%Y-%m-%d %H:%M:%S', manual_expire_date) as manual_expire_date,
+	strftime('%Y-%m-%d %H:%M:%S', manual_start_date) as manual_start_date,
+	COALESCE(trust_store_id, 0), COALESCE(per_ip, 0),
+	COALESCE(connect_address, ''), COALESCE(sni, ''), COALESCE(no_sni, 0),
+	COALESCE(deep_scan, 0), COALESCE(tls_grade, ''), COALESCE(check_caa, 0),
+	COALESCE(check_dane, 0), COALESCE(client_cert_id, 0), COALESCE(proxy, ''),
+	COALESCE(check_http, 0), COALESCE(warn_missing_hsts, 0)
+`
+
+// rowScanner 兼容 *sql.Row 和 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanWatchedDomain 扫描一行关注域名数据
+func scanWatchedDomain(row rowScanner) (*WatchedDomain, error) {
+	var wd WatchedDomain
+	var lastCheckTime sql.NullString
+	var nickname sql.NullString
+	var manualExpireDate sql.NullString
+	var manualStartDate sql.NullString
+
+	err := row.Scan(&wd.ID, &wd.Domain, &wd.Port, &wd.Protocol, &nickname, &wd.AddedTime, &lastCheckTime,
+		&wd.NotifyEnabled, &wd.NotifyThreshold, &wd.IsManual, &manualExpireDate, &manualStartDate,
+		&wd.TrustStoreID, &wd.Options.PerIP,
+		&wd.Options.ConnectAddress, &wd.Options.SNI, &wd.Options.NoSNI,
+		&wd.Options.DeepScan, &wd.TLSGrade, &wd.Options.CheckCAA,
+		&wd.Options.CheckDANE, &wd.Options.ClientCertID,
+		&wd.Options.Proxy, &wd.Options.CheckHTTP, &wd.Options.WarnMissingHSTS)
+	if err != nil {
+		return nil, err
+	}
+
+	if wd.Options.Proxy, err = openProxy(wd.Options.Proxy); err != nil {
+		wd.proxyErr = err
+	}
+	if nickname.Valid {
+		wd.Nickname = nickname.String
+	}
+	wd.UnicodeDomain = unicodeHost(wd.Domain)
+	if lastCheckTime.Valid {
+		wd.LastCheckTime = lastCheckTime.String
+	}
+	if manualExpireDate.Valid {
+		wd.ManualExpireDate = manualExpireDate.String
+	}
+	if manualStartDate.Valid {
+		wd.ManualStartDate = manualStartDate.String
+	}
+
+	return &wd, nil
+}
+
+// findWatchedDomain 按主机和端口查找关注域名，不存在时返回nil
+func (a *App) findWatchedDomain(host string, port int) (*WatchedDomain, error) {
+	row := a.db.QueryRow("SELECT "+watchedDomainColumns+" FROM watched_domains WHERE domain = ? AND port = ?", host, port)
+	wd, err := scanWatchedDomain(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return wd, err
+}
+
 // GetWatchedDomains 获取关注域名列表（并自动查询最新证书信息）
 func (a *App) GetWatchedDomains() WatchedDomainsResult {
 	if a.db == nil {
@@ -558,16 +718,7 @@ func (a *App) GetWatchedDomains() WatchedDomainsResult {
 	}
 
 	// 查询所有关注的域名
-	querySQL := `
-	SELECT id, domain, nickname, 
-	       strftime('%Y-%m-%d %H:%M:%S', added_time) as added_time,
-	       strftime('%Y-%m-%d %H:%M:%S', last_check_time) as last_check_time,
-	       notify_enabled, notify_threshold, is_manual,
-	       strftime('%Y-%m-%d %H:%M:%S', manual_expire_date) as manual_expire_date,
-	       strftime('%Y-%m-%d %H:%M:%S', manual_start_date) as manual_start_date
-	FROM watched_domains
-	ORDER BY added_time DESC
-	`
+	querySQL := "SELECT " + watchedDomainColumns + " FROM watched_domains ORDER BY added_time DESC"
 
 	rows, err := a.db.Query(querySQL)
 	if err != nil {
@@ -581,32 +732,11 @@ func (a *App) GetWatchedDomains() WatchedDomainsResult {
 	// 先收集所有域名
 	var domains []WatchedDomain
 	for rows.Next() {
-		var wd WatchedDomain
-		var lastCheckTime sql.NullString
-		var nickname sql.NullString
-		var manualExpireDate sql.NullString
-		var manualStartDate sql.NullString
-
-		err := rows.Scan(&wd.ID, &wd.Domain, &nickname, &wd.AddedTime, &lastCheckTime,
-			&wd.NotifyEnabled, &wd.NotifyThreshold, &wd.IsManual, &manualExpireDate, &manualStartDate)
+		wd, err := scanWatchedDomain(rows)
 		if err != nil {
 			continue
 		}
-
-		if nickname.Valid {
-			wd.Nickname = nickname.String
-		}
-		if lastCheckTime.Valid {
-			wd.LastCheckTime = lastCheckTime.String
-		}
-		if manualExpireDate.Valid {
-			wd.ManualExpireDate = manualExpireDate.String
-		}
-		if manualStartDate.Valid {
-			wd.ManualStartDate = manualStartDate.String
-		}
-
-		domains = append(domains, wd)
+		domains = append(domains, *wd)
 	}
 
 	// 使用并发查询证书信息，提高性能
@@ -626,16 +756,7 @@ func (a *App) GetWatchedDomains() WatchedDomainsResult {
 					expireTime, err := time.Parse("2006-01-02 15:04:05", domains[index].ManualExpireDate)
 					if err == nil {
 						daysRemaining := int(expireTime.Sub(time.Now()).Hours() / 24)
-						var status string
-						if daysRemaining < 0 {
-							status = "expired"
-						} else if daysRemaining <= 7 {
-							status = "danger"
-						} else if daysRemaining <= 30 {
-							status = "warning"
-						} else {
-							status = "safe"
-						}
+						status := statusForDays(daysRemaining)
 
 						// 生效时间：优先使用手动录入的，否则显示"-"
 						notBefore := "-"
@@ -645,6 +766,8 @@ func (a *App) GetWatchedDomains() WatchedDomainsResult {
 
 						domains[index].CertInfo = &CertificateInfo{
 							Domain:        domains[index].Domain,
+							Port:          domains[index].Port,
+							Protocol:      domains[index].Protocol,
 							Issuer:        "手动录入",
 							Subject:       domains[index].Domain,
 							NotBefore:     notBefore,
@@ -660,14 +783,35 @@ func (a *App) GetWatchedDomains() WatchedDomainsResult {
 				} else {
 					mu.Unlock()
 					// 自动查询最新证书信息（不保存到历史记录）
-					certResult := a.checkCertificateInternal(domains[index].Domain)
+					var certResult QueryResult
+					opts, err := a.watchedProbeOptions(&domains[index])
+					if err != nil {
+						certResult = QueryResult{Success: false, Error: err.Error(), Message: err.Error()}
+					} else {
+						// 深度扫描耗时较长，列表刷新时跳过，仅在刷新单个域名时执行
+						opts.DeepScan = false
+						certResult = a.probeTarget(a.probeContext(), domains[index].target(), opts)
+					}
 
 					mu.Lock()
 					if certResult.Success {
 						domains[index].CertInfo = certResult.Data
+						domains[index].CAAWarning = certResult.Data.CAA.mismatchWarning()
+						if opts.WarnMissingHSTS {
+							domains[index].HSTSWarning = certResult.Data.HTTP.missingHSTSWarning()
+						}
 
 						// 更新last_check_time
 						a.db.Exec("UPDATE watched_domains SET last_check_time = datetime('now', 'localtime') WHERE id = ?", domains[index].ID)
+
+						// 记录证书变更（各节点证书不一致时展示的节点可能每次不同，不作比较）
+						if !certResult.Data.IPInconsistent {
+							changes, err := a.recordCertSnapshot(domains[index].ID, certResult.Data)
+							if err != nil {
+								fmt.Printf("❌ %v\n", err)
+							}
+							domains[index].Changes = changes
+						}
 					}
 					mu.Unlock()
 				}
@@ -678,6 +822,10 @@ func (a *App) GetWatchedDomains() WatchedDomainsResult {
 		wg.Wait()
 	}
 
+	for i := range domains {
+		domains[i].Options.Proxy = redactProxy(domains[i].Options.Proxy)
+	}
+
 	return WatchedDomainsResult{
 		Success: true,
 		Message: fmt.Sprintf("查询到 %d 个关注域名", len(domains)),
@@ -686,6 +834,28 @@ func (a *App) GetWatchedDomains() WatchedDomainsResult {
 	}
 }
 
+// target 返回关注域名对应的探测目标
+func (wd *WatchedDomain) target() Target {
+	return Target{Host: wd.Domain, Port: wd.Port, Protocol: wd.Protocol}
+}
+
+// watchedProbeOptions 根据关注域名的设置构建探测选项
+func (a *App) watchedProbeOptions(wd *WatchedDomain) (ProbeOptions, error) {
+	if wd.proxyErr != nil {
+		return ProbeOptions{}, fmt.Errorf("%v，请重新设置该域名的代理", wd.proxyErr)
+	}
+	opts := wd.Options
+	roots, err := a.trustRoots(wd.TrustStoreID)
+	if err != nil {
+		return ProbeOptions{}, err
+	}
+	opts.Roots = roots
+	if opts.ClientCert, err = a.clientCertificate(opts.ClientCertID); err != nil {
+		return ProbeOptions{}, err
+	}
+	return opts, nil
+}
+
 // RemoveWatchedDomain 移除关注域名
 func (a *App) RemoveWatchedDomain(id int64) error {
 	if a.db == nil {
@@ -693,7 +863,11 @@ func (a *App) RemoveWatchedDomain(id int64) error {
 	}
 
 	_, err := a.db.Exec("DELETE FROM watched_domains WHERE id = ?", id)
-	return err
+	if err != nil {
+		return err
+	}
+	a.deleteCertHistory(id)
+	return nil
 }
 
 // UpdateWatchedDomainNickname 更新域名备注
@@ -715,16 +889,109 @@ func (a *App) RefreshWatchedDomain(domain string) QueryResult {
 		}
 	}
 
+	target, err := parseTarget(domain)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("无效的查询目标：%v", err),
+		}
+	}
+
+	// 已关注的目标使用其自身的探测设置（信任根等）
+	opts := ProbeOptions{}
+	wd, err := a.findWatchedDomain(target.Host, target.Port)
+	if err == nil && wd != nil {
+		opts, err = a.watchedProbeOptions(wd)
+	} else if err == nil {
+		opts.Roots, err = a.trustRoots(0)
+	}
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: err.Error(),
+		}
+	}
+
 	// 查询证书信息（不保存到历史记录）
-	result := a.checkCertificateInternal(domain)
+	result := a.probeTarget(a.probeContext(), target, opts)
 	if result.Success {
 		// 更新最后检查时间
-		a.db.Exec("UPDATE watched_domains SET last_check_time = datetime('now', 'localtime') WHERE domain = ?", domain)
+		a.db.Exec("UPDATE watched_domains SET last_check_time = datetime('now', 'localtime') WHERE domain = ? AND port = ?",
+			result.Data.Domain, result.Data.Port)
+
+		// 记录深度扫描评级
+		if result.Data.TLSScan != nil && result.Data.TLSScan.Error == "" {
+			a.db.Exec("UPDATE watched_domains SET tls_grade = ? WHERE domain = ? AND port = ?",
+				result.Data.TLSScan.Grade, result.Data.Domain, result.Data.Port)
+		}
+
+		// 记录证书变更
+		if wd != nil && !result.Data.IPInconsistent {
+			if _, err := a.recordCertSnapshot(wd.ID, result.Data); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+		}
 	}
 
 	return result
 }
 
+// UpdateWatchedDomainProtocol 更新关注域名的STARTTLS协议（空字符串表示直接TLS）
+func (a *App) UpdateWatchedDomainProtocol(id int64, protocol string) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	protocol, err := normalizeProtocol(protocol)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("UPDATE watched_domains SET protocol = ? WHERE id = ?", protocol, id)
+	if err != nil {
+		return fmt.Errorf("更新协议失败: %v", err)
+	}
+
+	fmt.Printf("✅ 更新协议成功: ID=%d, 协议=%s\n", id, protocol)
+	return nil
+}
+
+// UpdateWatchedDomainOptions 更新关注域名的探测选项；代理为 GetWatchedDomains 返回的隐藏了密码的地址时沿用已保存的密码
+func (a *App) UpdateWatchedDomainOptions(id int64, opts ProbeOptions) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	opts, err := opts.normalize()
+	if err != nil {
+		return err
+	}
+
+	var saved string
+	if err := a.db.QueryRow("SELECT COALESCE(proxy, '') FROM watched_domains WHERE id = ?", id).Scan(&saved); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("读取代理设置失败: %v", err)
+	}
+	if saved, err = openProxy(saved); err == nil {
+		opts.Proxy = keepProxyPassword(opts.Proxy, saved)
+	}
+	proxy, err := sealProxy(opts.Proxy)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec("UPDATE watched_domains SET per_ip = ?, connect_address = ?, sni = ?, no_sni = ?, deep_scan = ?, check_caa = ?, check_dane = ?, proxy = ?, check_http = ?, warn_missing_hsts = ? WHERE id = ?",
+		opts.PerIP, opts.ConnectAddress, opts.SNI, opts.NoSNI, opts.DeepScan, opts.CheckCAA, opts.CheckDANE, proxy, opts.CheckHTTP, opts.WarnMissingHSTS, id)
+	if err != nil {
+		return fmt.Errorf("更新探测选项失败: %v", err)
+	}
+
+	fmt.Printf("✅ 更新探测选项成功: ID=%d, 逐IP探测=%v, 连接地址=%s, SNI=%s, 不发送SNI=%v, 深度扫描=%v, CAA检查=%v, DANE检查=%v, 代理=%s, HTTP检查=%v, 未启用HSTS警告=%v\n",
+		id, opts.PerIP, opts.ConnectAddress, opts.SNI, opts.NoSNI, opts.DeepScan, opts.CheckCAA, opts.CheckDANE, redactProxy(opts.Proxy), opts.CheckHTTP, opts.WarnMissingHSTS)
+	return nil
+}
+
 // UpdateNotifySettings 更新通知设置
 func (a *App) UpdateNotifySettings(id int64, enabled bool, threshold int) error {
 	if a.db == nil {
@@ -811,6 +1078,30 @@ func parseDateTime(dateStr string) (string, error) {
 	return "", fmt.Errorf("日期格式错误，请使用 YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS 格式")
 }
 
+// encodeStringList 将字符串列表编码为JSON文本存入数据库，空列表存为空字符串
+func encodeStringList(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// decodeStringList 解析数据库中的JSON字符串列表
+func decodeStringList(text string) []string {
+	if text == "" {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(text), &list); err != nil {
+		return nil
+	}
+	return list
+}
+
 // DisableManualMode 禁用手动模式，恢复自动查询
 func (a *App) DisableManualMode(id int64) error {
 	if a.db == nil {
@@ -829,13 +1120,17 @@ func (a *App) DisableManualMode(id int64) error {
 
 // NotificationItem 通知项
 type NotificationItem struct {
-	ID            int64  `json:"id"`
-	Domain        string `json:"domain"`
-	Nickname      string `json:"nickname,omitempty"`
-	DaysRemaining int    `json:"daysRemaining"`
-	NotAfter      string `json:"notAfter"`
-	Threshold     int    `json:"threshold"`
-	Status        string `json:"status"`
+	ID            int64   `json:"id"`
+	Domain        string  `json:"domain"`
+	Port          int     `json:"port"`
+	Nickname      string  `json:"nickname,omitempty"`
+	DaysRemaining int     `json:"daysRemaining"`
+	NotAfter      string  `json:"notAfter"`
+	Threshold     int     `json:"threshold"`
+	Status        string  `json:"status"`
+	Reason        string  `json:"reason,omitempty"`   // 通知原因（证书校验失败时为错误信息）
+	Kind          string  `json:"kind,omitempty"`     // 为空表示关注域名，"client_cert" 表示客户端证书，"cert_change" 表示证书变更
+	EventIDs      []int64 `json:"eventIds,omitempty"` // 证书变更通知包含的事件，展示后通过 AcknowledgeCertEvents 确认
 }
 
 // NotificationResult 通知检查结果
@@ -878,6 +1173,23 @@ func (a *App) CheckNotifications() NotificationResult {
 			continue
 		}
 
+		// 证书校验失败（不受信任、主机名不匹配、已吊销）或各节点证书不一致时，无论剩余天数都需要通知
+		if isVerifyFailure(domain.CertInfo.Status) || domain.CertInfo.IPInconsistent {
+			reasons := append(append([]string{}, domain.CertInfo.VerifyErrors...), domain.CertInfo.Warnings...)
+			notifications = append(notifications, NotificationItem{
+				ID:            domain.ID,
+				Domain:        domain.Domain,
+				Port:          domain.Port,
+				Nickname:      domain.Nickname,
+				DaysRemaining: domain.CertInfo.DaysRemaining,
+				NotAfter:      domain.CertInfo.NotAfter,
+				Threshold:     domain.NotifyThreshold,
+				Status:        domain.CertInfo.Status,
+				Reason:        strings.Join(reasons, "; "),
+			})
+			continue
+		}
+
 		// 跳过已过期的（单独处理）
 		if domain.CertInfo.DaysRemaining < 0 {
 			continue
@@ -888,6 +1200,7 @@ func (a *App) CheckNotifications() NotificationResult {
 			notifications = append(notifications, NotificationItem{
 				ID:            domain.ID,
 				Domain:        domain.Domain,
+				Port:          domain.Port,
 				Nickname:      domain.Nickname,
 				DaysRemaining: domain.CertInfo.DaysRemaining,
 				NotAfter:      domain.CertInfo.NotAfter,
@@ -897,6 +1210,38 @@ func (a *App) CheckNotifications() NotificationResult {
 		}
 	}
 
+	// 证书意外变更（颁发者变化、SAN被移除、过期时间提前）在用户确认前持续提醒
+	changes, err := a.pendingCertChangeNotifications(domainsResult.Domains)
+	if err != nil {
+		fmt.Printf("❌ 查询证书变更失败: %v\n", err)
+	}
+	notifications = append(notifications, changes...)
+
+	// 客户端证书过期同样会导致探测失败，已过期或剩余天数不超过阈值时通知
+	clientCerts := a.GetClientCertificates()
+	for _, cc := range clientCerts.Certificates {
+		if !cc.NotifyEnabled || cc.DaysRemaining > cc.NotifyThreshold {
+			continue
+		}
+		reason := "客户端证书即将过期"
+		if cc.DaysRemaining < 0 {
+			reason = "客户端证书已过期"
+		}
+		if cc.UsedBy > 0 {
+			reason += fmt.Sprintf("，%d 个关注域名使用该证书", cc.UsedBy)
+		}
+		notifications = append(notifications, NotificationItem{
+			ID:            cc.ID,
+			Domain:        cc.Name,
+			DaysRemaining: cc.DaysRemaining,
+			NotAfter:      cc.NotAfter,
+			Threshold:     cc.NotifyThreshold,
+			Status:        cc.Status,
+			Reason:        reason,
+			Kind:          "client_cert",
+		})
+	}
+
 	if len(notifications) == 0 {
 		return NotificationResult{
 			Success: true,
@@ -975,7 +1320,7 @@ func (a *App) ImportDomainsFromText(text string) ImportDomainsResult {
 			continue
 		}
 
-		// 支持CSV格式：域名,备注
+		// 支持CSV格式：域名,备注（域名可带端口，如 example.com:8443）
 		parts := strings.Split(line, ",")
 		domain := strings.TrimSpace(parts[0])
 		var nickname string
@@ -1003,9 +1348,16 @@ func (a *App) ImportDomainsFromText(text string) ImportDomainsResult {
 	var failedDomains []string
 
 	for _, item := range domains {
-		// 检查是否已存在
+		target, err := parseTarget(item.Domain)
+		if err != nil {
+			failedCount++
+			failedDomains = append(failedDomains, fmt.Sprintf("%s (%v)", item.Domain, err))
+			continue
+		}
+
+		// 检查是否已存在（同一主机的不同端口视为不同目标）
 		var count int
-		err := a.db.QueryRow("SELECT COUNT(*) FROM watched_domains WHERE domain = ?", item.Domain).Scan(&count)
+		err = a.db.QueryRow("SELECT COUNT(*) FROM watched_domains WHERE domain = ? AND port = ?", target.Host, target.Port).Scan(&count)
 		if err != nil {
 			failedCount++
 			failedDomains = append(failedDomains, fmt.Sprintf("%s (查询失败)", item.Domain))
@@ -1019,8 +1371,8 @@ func (a *App) ImportDomainsFromText(text string) ImportDomainsResult {
 		}
 
 		// 插入域名
-		insertSQL := "INSERT INTO watched_domains (domain, nickname) VALUES (?, ?)"
-		_, err = a.db.Exec(insertSQL, item.Domain, item.Nickname)
+		insertSQL := "INSERT INTO watched_domains (domain, port, protocol, nickname) VALUES (?, ?, ?, ?)"
+		_, err = a.db.Exec(insertSQL, target.Host, target.Port, target.Protocol, item.Nickname)
 		if err != nil {
 			failedCount++
 			failedDomains = append(failedDomains, fmt.Sprintf("%s (插入失败)", item.Domain))
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// caaFlagCritical CAA记录的关键标志位，CA不认识带该标志的标签时必须拒绝签发
+const caaFlagCritical = 128
+
+// caaKnownTags 已知的CAA标签（RFC 8659及后续扩展）
+var caaKnownTags = map[string]bool{
+	"issue":        true,
+	"issuewild":    true,
+	"iodef":        true,
+	"issuemail":    true,
+	"issuevmc":     true,
+	"contactemail": true,
+	"contactphone": true,
+}
+
+// caaIssuers CAA记录中常见的CA标识与证书颁发者组织名（小写关键字）的对应关系，
+// 未列出的标识按域名主体（如 harica.gr 中的 harica）匹配
+var caaIssuers = map[string][]string{
+	"letsencrypt.org":           {"let's encrypt"},
+	"digicert.com":              {"digicert"},
+	"digitalcertvalidation.com": {"digicert"},
+	"symantec.com":              {"digicert", "symantec"},
+	"geotrust.com":              {"digicert", "geotrust"},
+	"thawte.com":                {"digicert", "thawte"},
+	"rapidssl.com":              {"digicert", "rapidssl"},
+	"sectigo.com":               {"sectigo", "comodo", "usertrust"},
+	"comodoca.com":              {"sectigo", "comodo", "usertrust"},
+	"comodo.com":                {"sectigo", "comodo", "usertrust"},
+	"usertrust.com":             {"sectigo", "comodo", "usertrust"},
+	"zerossl.com":               {"zerossl"},
+	"globalsign.com":            {"globalsign"},
+	"pki.goog":                  {"google trust services"},
+	"amazon.com":                {"amazon"},
+	"amazontrust.com":           {"amazon"},
+	"awstrust.com":              {"amazon"},
+	"amazonaws.com":             {"amazon"},
+	"godaddy.com":               {"godaddy", "starfield"},
+	"starfieldtech.com":         {"godaddy", "starfield"},
+	"entrust.net":               {"entrust", "affirmtrust"},
+	"affirmtrust.com":           {"entrust", "affirmtrust"},
+	"buypass.com":               {"buypass"},
+	"buypass.no":                {"buypass"},
+	"ssl.com":                   {"ssl corporation", "ssl.com"},
+	"identrust.com":             {"identrust"},
+	"certum.pl":                 {"certum", "unizeto", "asseco"},
+	"microsoft.com":             {"microsoft"},
+	"trustasia.com":             {"trustasia"},
+	"cfca.com.cn":               {"cfca", "china financial certification authority"},
+	"gdca.com.cn":               {"gdca", "global digital cybersecurity"},
+	"sheca.com":                 {"sheca", "shanghai electronic certification"},
+}
+
+// CAARecord 单条CAA记录
+type CAARecord struct {
+	Flags int    `json:"flags"`
+	Tag   string `json:"tag"` // "issue", "issuewild", "iodef" 等
+	Value string `json:"value"`
+}
+
+// CAAInfo CAA检查结果
+type CAAInfo struct {
+	Domain    string      `json:"domain,omitempty"`  // 生效的CAA记录所在域名（沿域名树向上查找到的第一个）
+	Records   []CAARecord `json:"records,omitempty"` // 生效的CAA记录
+	IssuerOrg string      `json:"issuerOrg"`         // 证书颁发者组织
+	Allowed   []string    `json:"allowed,omitempty"` // 适用于该证书的 issue/issuewild 授权的CA标识
+	Permitted bool        `json:"permitted"`         // CAA是否允许该颁发者（没有CAA记录时任何CA均可签发）
+	Reason    string      `json:"reason,omitempty"`  // 不允许的原因
+	Error     string      `json:"error,omitempty"`   // 查询失败原因
+}
+
+// mismatchWarning 返回CAA不允许当前颁发者时的警告，允许或查询失败时为空
+func (c *CAAInfo) mismatchWarning() string {
+	if c == nil || c.Permitted || c.Error != "" {
+		return ""
+	}
+	return fmt.Sprintf("CAA记录（%s）不允许当前颁发者 %s 签发：%s", c.Domain, c.IssuerOrg, c.Reason)
+}
+
+// checkCAA 查找主机名生效的CAA记录，检查是否允许证书的颁发者签发
+func (a *App) checkCAA(ctx context.Context, info *CertificateInfo, host string, leaf *x509.Certificate) {
+	result := &CAAInfo{IssuerOrg: issuerOrganization(leaf)}
+	info.CAA = result
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	domain, records, err := a.lookupCAA(ctx, host)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Domain = domain
+	result.Records = records
+
+	wildcard := false
+	for _, name := range leaf.DNSNames {
+		if strings.HasPrefix(name, "*.") {
+			wildcard = true
+		}
+	}
+	result.Allowed, result.Permitted, result.Reason = evaluateCAA(records, result.IssuerOrg, wildcard)
+
+	if warning := result.mismatchWarning(); warning != "" {
+		info.Warnings = append(info.Warnings, warning)
+	}
+}
+
+// lookupCAA 从主机名开始沿域名树向上查找，返回第一个存在CAA记录的域名及其记录（RFC 8659 3）
+// 所有上级域名都没有CAA记录时返回空
+func (a *App) lookupCAA(ctx context.Context, host string) (string, []CAARecord, error) {
+	resolver := a.recordResolver()
+	name := strings.TrimSuffix(strings.ToLower(host), ".")
+
+	// 不查询顶级域名
+	for strings.Contains(name, ".") {
+		rdata, err := resolver.LookupRecords(ctx, name, dnsTypeCAA)
+		if err != nil {
+			return "", nil, fmt.Errorf("查询 %s 的CAA记录失败: %v", name, err)
+		}
+		if len(rdata) > 0 {
+			records := make([]CAARecord, 0, len(rdata))
+			for _, data := range rdata {
+				record, err := parseCAARecord(data)
+				if err != nil {
+					return "", nil, fmt.Errorf("解析 %s 的CAA记录失败: %v", name, err)
+				}
+				records = append(records, record)
+			}
+			return name, records, nil
+		}
+		name = name[strings.Index(name, ".")+1:]
+	}
+	return "", nil, nil
+}
+
+// parseCAARecord 解析CAA记录的RDATA：flags(1) + tag长度(1) + tag + value
+func parseCAARecord(data []byte) (CAARecord, error) {
+	if len(data) < 2 || len(data) < 2+int(data[1]) || data[1] == 0 {
+		return CAARecord{}, fmt.Errorf("记录长度无效")
+	}
+	tagLen := int(data[1])
+	return CAARecord{
+		Flags: int(data[0]),
+		Tag:   strings.ToLower(string(data[2 : 2+tagLen])),
+		Value: string(data[2+tagLen:]),
+	}, nil
+}
+
+// evaluateCAA 判断CAA记录集是否允许颁发者签发，返回适用的授权CA标识、是否允许和不允许的原因
+// 通配符证书优先使用 issuewild 记录，没有 issuewild 时使用 issue 记录
+func evaluateCAA(records []CAARecord, issuerOrg string, wildcard bool) ([]string, bool, string) {
+	if len(records) == 0 {
+		return nil, true, ""
+	}
+
+	for _, record := range records {
+		if record.Flags&caaFlagCritical != 0 && !caaKnownTags[record.Tag] {
+			return nil, false, fmt.Sprintf("包含无法识别的关键标签 %s", record.Tag)
+		}
+	}
+
+	tag := "issue"
+	if wildcard {
+		for _, record := range records {
+			if record.Tag == "issuewild" {
+				tag = "issuewild"
+				break
+			}
+		}
+	}
+
+	var allowed []string
+	found := false
+	for _, record := range records {
+		if record.Tag != tag {
+			continue
+		}
+		found = true
+		// 值的格式为 "CA标识; 参数"，标识为空（如 ";"）表示禁止任何CA签发
+		identifier := strings.ToLower(strings.TrimSpace(strings.SplitN(record.Value, ";", 2)[0]))
+		if identifier != "" {
+			allowed = append(allowed, identifier)
+		}
+	}
+
+	// 记录集中没有适用的签发标签（如只有iodef）时不限制签发
+	if !found {
+		return nil, true, ""
+	}
+	if len(allowed) == 0 {
+		return nil, false, fmt.Sprintf("%s 记录禁止任何CA签发", tag)
+	}
+	for _, identifier := range allowed {
+		if caaIdentifierMatches(identifier, issuerOrg) {
+			return allowed, true, ""
+		}
+	}
+	return allowed, false, fmt.Sprintf("仅允许 %s", strings.Join(allowed, ", "))
+}
+
+// caaIdentifierMatches 判断CAA中的CA标识是否对应颁发者组织
+func caaIdentifierMatches(identifier, issuerOrg string) bool {
+	org := strings.ToLower(strings.ReplaceAll(issuerOrg, "’", "'"))
+	if org == "" {
+		return false
+	}
+
+	if keywords, ok := caaIssuers[identifier]; ok {
+		for _, keyword := range keywords {
+			if strings.Contains(org, keyword) {
+				return true
+			}
+		}
+		return false
+	}
+
+	labels := strings.Split(identifier, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	name := labels[len(labels)-2]
+	return len(name) >= 4 && strings.Contains(org, name)
+}
+
+// issuerOrganization 返回证书颁发者的组织名，没有组织名时使用CN
+func issuerOrganization(cert *x509.Certificate) string {
+	if len(cert.Issuer.Organization) > 0 {
+		return strings.Join(cert.Issuer.Organization, ", ")
+	}
+	return cert.Issuer.CommonName
+}
+
+// caaApplicable 判断主机是否需要进行基于DNS记录的检查（IP地址没有CAA、TLSA记录）
+func caaApplicable(host string) bool {
+	return net.ParseIP(host) == nil && strings.Contains(host, ".")
+}
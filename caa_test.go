@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// fakeRecordResolver 按域名和记录类型返回固定记录的解析器
+type fakeRecordResolver struct {
+	records map[string][][]byte
+	queries []string
+}
+
+func (r *fakeRecordResolver) LookupRecords(ctx context.Context, name string, qtype uint16) ([][]byte, error) {
+	r.queries = append(r.queries, name)
+	return r.records[name], nil
+}
+
+// caaRDATA 编码CAA记录的RDATA
+func caaRDATA(flags byte, tag, value string) []byte {
+	return append(append([]byte{flags, byte(len(tag))}, tag...), value...)
+}
+
+func TestParseCAARecord(t *testing.T) {
+	record, err := parseCAARecord(caaRDATA(128, "Issue", "letsencrypt.org; validationmethods=dns-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Flags != 128 || record.Tag != "issue" || record.Value != "letsencrypt.org; validationmethods=dns-01" {
+		t.Fatalf("解析结果不正确: %+v", record)
+	}
+
+	for _, data := range [][]byte{nil, {0}, {0, 0}, {0, 5, 'i', 's'}} {
+		if _, err := parseCAARecord(data); err == nil {
+			t.Errorf("parseCAARecord(%v) 应返回错误", data)
+		}
+	}
+}
+
+func TestEvaluateCAA(t *testing.T) {
+	issue := func(value string) CAARecord { return CAARecord{Tag: "issue", Value: value} }
+	issuewild := func(value string) CAARecord { return CAARecord{Tag: "issuewild", Value: value} }
+
+	tests := []struct {
+		name      string
+		records   []CAARecord
+		issuer    string
+		wildcard  bool
+		permitted bool
+		reason    string
+	}{
+		{"没有CAA记录", nil, "Let's Encrypt", false, true, ""},
+		{"允许的CA", []CAARecord{issue("letsencrypt.org")}, "Let's Encrypt", false, true, ""},
+		{"带参数的标识", []CAARecord{issue("LetsEncrypt.org; accounturi=https://example")}, "Let's Encrypt", false, true, ""},
+		{"多个CA之一", []CAARecord{issue("digicert.com"), issue("sectigo.com")}, "Sectigo Limited", false, true, ""},
+		{"不允许的CA", []CAARecord{issue("digicert.com")}, "Let's Encrypt", false, false, "仅允许 digicert.com"},
+		{"禁止签发", []CAARecord{issue(";")}, "Let's Encrypt", false, false, "禁止任何CA签发"},
+		{"只有iodef", []CAARecord{{Tag: "iodef", Value: "mailto:security@example.com"}}, "Let's Encrypt", false, true, ""},
+		{"通配符优先使用issuewild", []CAARecord{issue("letsencrypt.org"), issuewild("digicert.com")}, "Let's Encrypt", true, false, "仅允许 digicert.com"},
+		{"通配符没有issuewild时使用issue", []CAARecord{issue("letsencrypt.org")}, "Let's Encrypt", true, true, ""},
+		{"非通配符忽略issuewild", []CAARecord{issue("letsencrypt.org"), issuewild(";")}, "Let's Encrypt", false, true, ""},
+		{"未知的关键标签", []CAARecord{issue("letsencrypt.org"), {Flags: 128, Tag: "future"}}, "Let's Encrypt", false, false, "关键标签 future"},
+		{"未知的非关键标签", []CAARecord{issue("letsencrypt.org"), {Tag: "future"}}, "Let's Encrypt", false, true, ""},
+		{"未收录的CA按域名匹配", []CAARecord{issue("example-ca.com")}, "Example-CA Inc", false, true, ""},
+		{"颁发者为空", []CAARecord{issue("letsencrypt.org")}, "", false, false, "仅允许 letsencrypt.org"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, permitted, reason := evaluateCAA(tt.records, tt.issuer, tt.wildcard)
+			if permitted != tt.permitted || !strings.Contains(reason, tt.reason) {
+				t.Fatalf("evaluateCAA = %v, %q，期望 %v, %q", permitted, reason, tt.permitted, tt.reason)
+			}
+		})
+	}
+}
+
+func TestLookupCAAClimbsTree(t *testing.T) {
+	resolver := &fakeRecordResolver{records: map[string][][]byte{
+		"example.com": {caaRDATA(0, "issue", "letsencrypt.org")},
+	}}
+	a := &App{dnsResolver: resolver}
+
+	domain, records, err := a.lookupCAA(context.Background(), "WWW.Sub.Example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain != "example.com" || len(records) != 1 || records[0].Value != "letsencrypt.org" {
+		t.Fatalf("lookupCAA = %s, %+v", domain, records)
+	}
+	want := "www.sub.example.com,sub.example.com,example.com"
+	if got := strings.Join(resolver.queries, ","); got != want {
+		t.Fatalf("查询顺序为 %s，期望 %s", got, want)
+	}
+
+	// 所有上级域名都没有记录，且不查询顶级域名
+	resolver.queries = nil
+	domain, records, err = a.lookupCAA(context.Background(), "www.example.org")
+	if err != nil || domain != "" || records != nil {
+		t.Fatalf("没有CAA记录时应返回空，得到 %s, %+v, %v", domain, records, err)
+	}
+	if len(resolver.queries) != 2 {
+		t.Fatalf("查询了 %v，不应查询顶级域名", resolver.queries)
+	}
+}
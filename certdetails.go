@@ -0,0 +1,158 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+)
+
+// minRSAKeySize RSA密钥的最小安全长度
+const minRSAKeySize = 2048
+
+// keyUsageNames 密钥用法名称
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digitalSignature"},
+	{x509.KeyUsageContentCommitment, "contentCommitment"},
+	{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "keyAgreement"},
+	{x509.KeyUsageCertSign, "keyCertSign"},
+	{x509.KeyUsageCRLSign, "cRLSign"},
+	{x509.KeyUsageEncipherOnly, "encipherOnly"},
+	{x509.KeyUsageDecipherOnly, "decipherOnly"},
+}
+
+// extKeyUsageNames 扩展密钥用法名称
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "any",
+	x509.ExtKeyUsageServerAuth:                     "serverAuth",
+	x509.ExtKeyUsageClientAuth:                     "clientAuth",
+	x509.ExtKeyUsageCodeSigning:                    "codeSigning",
+	x509.ExtKeyUsageEmailProtection:                "emailProtection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "ipsecEndSystem",
+	x509.ExtKeyUsageIPSECTunnel:                    "ipsecTunnel",
+	x509.ExtKeyUsageIPSECUser:                      "ipsecUser",
+	x509.ExtKeyUsageTimeStamping:                   "timeStamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSPSigning",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "msSGC",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "nsSGC",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "msCodeCom",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "msKernelCode",
+}
+
+// applyCertificateDetails 填充叶子证书的密钥、签名算法、指纹、密钥用法和基本约束，并标记弱配置
+func applyCertificateDetails(info *CertificateInfo, cert *x509.Certificate) {
+	info.PublicKeyAlgorithm, info.PublicKeySize = publicKeyInfo(cert)
+	info.SignatureAlgorithm = cert.SignatureAlgorithm.String()
+	info.FingerprintSHA1 = fingerprintSHA1(cert)
+	info.FingerprintSHA256 = fingerprintSHA256(cert)
+	info.PublicKeySHA256 = publicKeySHA256(cert)
+	info.KeyUsage = keyUsageList(cert.KeyUsage)
+	info.ExtKeyUsage = extKeyUsageList(cert)
+	info.BasicConstraints = basicConstraints(cert)
+	info.WeakFlags = weakFlags(cert, info.PublicKeyAlgorithm, info.PublicKeySize)
+}
+
+// publicKeyInfo 返回公钥算法和长度（位）
+func publicKeyInfo(cert *x509.Certificate) (string, int) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+	return cert.PublicKeyAlgorithm.String(), 0
+}
+
+// fingerprintSHA1 返回证书DER编码的SHA-1指纹
+func fingerprintSHA1(cert *x509.Certificate) string {
+	sum := sha1.Sum(cert.Raw)
+	return formatFingerprint(sum[:])
+}
+
+// publicKeySHA256 返回公钥（SubjectPublicKeyInfo）的SHA-256指纹
+func publicKeySHA256(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return formatFingerprint(sum[:])
+}
+
+// keyUsageList 返回密钥用法名称列表
+func keyUsageList(usage x509.KeyUsage) []string {
+	var names []string
+	for _, ku := range keyUsageNames {
+		if usage&ku.usage != 0 {
+			names = append(names, ku.name)
+		}
+	}
+	return names
+}
+
+// extKeyUsageList 返回扩展密钥用法名称列表，未知的用法以OID表示
+func extKeyUsageList(cert *x509.Certificate) []string {
+	var names []string
+	for _, eku := range cert.ExtKeyUsage {
+		if name, ok := extKeyUsageNames[eku]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("unknown(%d)", eku))
+		}
+	}
+	for _, oid := range cert.UnknownExtKeyUsage {
+		names = append(names, oid.String())
+	}
+	return names
+}
+
+// basicConstraints 返回基本约束描述，如 "CA:FALSE"、"CA:TRUE, pathlen:0"，证书不含该扩展时为空
+func basicConstraints(cert *x509.Certificate) string {
+	if !cert.BasicConstraintsValid {
+		return ""
+	}
+	if !cert.IsCA {
+		return "CA:FALSE"
+	}
+	if cert.MaxPathLen > 0 || cert.MaxPathLenZero {
+		return fmt.Sprintf("CA:TRUE, pathlen:%d", cert.MaxPathLen)
+	}
+	return "CA:TRUE"
+}
+
+// weakFlags 检查弱配置：RSA密钥不足2048位、SHA-1/MD5签名、声明了扩展密钥用法但不包含serverAuth
+func weakFlags(cert *x509.Certificate, keyAlgorithm string, keySize int) []string {
+	var flags []string
+
+	if keyAlgorithm == "RSA" && keySize < minRSAKeySize {
+		flags = append(flags, fmt.Sprintf("RSA密钥长度仅 %d 位（应不低于 %d 位）", keySize, minRSAKeySize))
+	}
+
+	switch cert.SignatureAlgorithm {
+	case x509.SHA1WithRSA, x509.ECDSAWithSHA1, x509.DSAWithSHA1:
+		flags = append(flags, fmt.Sprintf("使用SHA-1签名（%s）", cert.SignatureAlgorithm))
+	case x509.MD5WithRSA, x509.MD2WithRSA:
+		flags = append(flags, fmt.Sprintf("使用MD5/MD2签名（%s）", cert.SignatureAlgorithm))
+	}
+
+	// 没有扩展密钥用法扩展时用途不受限制（RFC 5280 4.2.1.12），只检查声明了扩展密钥用法的证书
+	if len(cert.ExtKeyUsage)+len(cert.UnknownExtKeyUsage) > 0 {
+		hasServerAuth := false
+		for _, eku := range cert.ExtKeyUsage {
+			if eku == x509.ExtKeyUsageServerAuth || eku == x509.ExtKeyUsageAny {
+				hasServerAuth = true
+			}
+		}
+		if !hasServerAuth {
+			flags = append(flags, "缺少serverAuth扩展密钥用法")
+		}
+	}
+
+	return flags
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"testing"
+)
+
+func TestWeakFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		cert    *x509.Certificate
+		keyAlg  string
+		keySize int
+		want    []string
+	}{
+		{"没有扩展密钥用法", &x509.Certificate{SignatureAlgorithm: x509.SHA256WithRSA}, "RSA", 2048, nil},
+		{"包含serverAuth", &x509.Certificate{ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth}}, "ECDSA", 256, nil},
+		{"anyExtendedKeyUsage", &x509.Certificate{ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}, "ECDSA", 256, nil},
+		{"仅clientAuth", &x509.Certificate{ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, "ECDSA", 256,
+			[]string{"缺少serverAuth扩展密钥用法"}},
+		{"仅未知的扩展密钥用法", &x509.Certificate{UnknownExtKeyUsage: []asn1.ObjectIdentifier{{1, 2, 3, 4}}}, "ECDSA", 256,
+			[]string{"缺少serverAuth扩展密钥用法"}},
+		{"短RSA密钥和SHA-1签名", &x509.Certificate{SignatureAlgorithm: x509.SHA1WithRSA}, "RSA", 1024,
+			[]string{"RSA密钥长度仅 1024 位（应不低于 2048 位）", "使用SHA-1签名（SHA1-RSA）"}},
+		{"MD5签名", &x509.Certificate{SignatureAlgorithm: x509.MD5WithRSA}, "RSA", 2048,
+			[]string{"使用MD5/MD2签名（MD5-RSA）"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weakFlags(tt.cert, tt.keyAlg, tt.keySize)
+			if len(got) != len(tt.want) {
+				t.Fatalf("weakFlags = %q，期望 %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("weakFlags = %q，期望 %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
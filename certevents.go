@@ -0,0 +1,413 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 证书变更事件类型
+const (
+	CertEventFirstSeen  = "first_seen"  // 首次记录
+	CertEventSerial     = "serial"      // 序列号变化（证书已更换）
+	CertEventIssuer     = "issuer"      // 颁发者变化
+	CertEventKey        = "key"         // 公钥变化
+	CertEventSANAdded   = "san_added"   // 新增SAN
+	CertEventSANRemoved = "san_removed" // 移除SAN
+	CertEventExpiry     = "expiry"      // 过期时间变化
+)
+
+// CertEvent 关注域名的证书变更事件
+type CertEvent struct {
+	ID                int64  `json:"id"`
+	WatchedDomainID   int64  `json:"watchedDomainId"`
+	Kind              string `json:"kind"`
+	OldValue          string `json:"oldValue,omitempty"`
+	NewValue          string `json:"newValue,omitempty"`
+	Unexpected        bool   `json:"unexpected"` // 非常规续期的变化（颁发者变化、SAN被移除、过期时间提前），会触发通知
+	Description       string `json:"description"`
+	FingerprintSHA256 string `json:"fingerprintSha256,omitempty"` // 变更后的证书指纹
+	EventTime         string `json:"eventTime"`
+}
+
+// CertSnapshot 关注域名最近一次观察到的证书
+type CertSnapshot struct {
+	SerialNumber      string   `json:"serialNumber"`
+	Issuer            string   `json:"issuer"`
+	PublicKeySHA256   string   `json:"publicKeySha256"`
+	SANs              []string `json:"sans"` // DNS和IP类型的SAN（IP以 "IP:" 开头），已排序
+	NotAfter          string   `json:"notAfter"`
+	FingerprintSHA256 string   `json:"fingerprintSha256"`
+	FirstSeenTime     string   `json:"firstSeenTime,omitempty"` // 开始观察的时间
+	CheckedTime       string   `json:"checkedTime,omitempty"`   // 最近一次检查的时间
+}
+
+// DomainTimelineResult 关注域名的证书变更时间线
+type DomainTimelineResult struct {
+	Success  bool          `json:"success"`
+	Message  string        `json:"message"`
+	Domain   string        `json:"domain"`
+	Snapshot *CertSnapshot `json:"snapshot,omitempty"` // 当前证书，尚未检查过时为nil
+	Events   []CertEvent   `json:"events"`             // 按时间倒序
+	Error    string        `json:"error,omitempty"`
+}
+
+// newCertSnapshot 从证书信息生成快照
+func newCertSnapshot(info *CertificateInfo) CertSnapshot {
+	var sans []string
+	for _, name := range info.SANDomains {
+		sans = append(sans, strings.ToLower(name))
+	}
+	for _, ip := range info.SANIPs {
+		sans = append(sans, "IP:"+ip)
+	}
+	sort.Strings(sans)
+
+	return CertSnapshot{
+		SerialNumber:      info.SerialNumber,
+		Issuer:            info.Issuer,
+		PublicKeySHA256:   info.PublicKeySHA256,
+		SANs:              sans,
+		NotAfter:          info.NotAfter,
+		FingerprintSHA256: info.FingerprintSHA256,
+	}
+}
+
+// diffSnapshots 比较前后两次观察到的证书，返回变更事件
+// 常规续期（序列号、公钥变化，过期时间延后，新增SAN）不标记为意外
+func diffSnapshots(old, current CertSnapshot) []CertEvent {
+	var events []CertEvent
+	add := func(kind, oldValue, newValue string, unexpected bool) {
+		events = append(events, CertEvent{
+			Kind:              kind,
+			OldValue:          oldValue,
+			NewValue:          newValue,
+			Unexpected:        unexpected,
+			FingerprintSHA256: current.FingerprintSHA256,
+		})
+	}
+
+	if old.SerialNumber != current.SerialNumber {
+		add(CertEventSerial, old.SerialNumber, current.SerialNumber, false)
+	}
+	if old.Issuer != current.Issuer {
+		add(CertEventIssuer, old.Issuer, current.Issuer, true)
+	}
+	if old.PublicKeySHA256 != "" && old.PublicKeySHA256 != current.PublicKeySHA256 {
+		add(CertEventKey, old.PublicKeySHA256, current.PublicKeySHA256, false)
+	}
+
+	added, removed := diffStrings(old.SANs, current.SANs)
+	if len(added) > 0 {
+		add(CertEventSANAdded, "", strings.Join(added, ", "), false)
+	}
+	if len(removed) > 0 {
+		add(CertEventSANRemoved, strings.Join(removed, ", "), "", true)
+	}
+
+	if old.NotAfter != current.NotAfter {
+		add(CertEventExpiry, old.NotAfter, current.NotAfter, current.NotAfter < old.NotAfter)
+	}
+
+	for i := range events {
+		events[i].Description = events[i].describe()
+	}
+	return events
+}
+
+// diffStrings 返回 current 相对 old 新增和移除的元素
+func diffStrings(old, current []string) (added, removed []string) {
+	seen := make(map[string]bool, len(old))
+	for _, s := range old {
+		seen[s] = true
+	}
+	for _, s := range current {
+		if !seen[s] {
+			added = append(added, s)
+		}
+		delete(seen, s)
+	}
+	for _, s := range old {
+		if seen[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+// describe 返回事件说明
+func (e CertEvent) describe() string {
+	switch e.Kind {
+	case CertEventFirstSeen:
+		return fmt.Sprintf("开始记录证书（序列号 %s）", e.NewValue)
+	case CertEventSerial:
+		return fmt.Sprintf("证书已更换：序列号 %s → %s", e.OldValue, e.NewValue)
+	case CertEventIssuer:
+		return fmt.Sprintf("颁发者变化：%s → %s", orNone(e.OldValue), orNone(e.NewValue))
+	case CertEventKey:
+		return "公钥已更换"
+	case CertEventSANAdded:
+		return "新增SAN：" + e.NewValue
+	case CertEventSANRemoved:
+		return "移除SAN：" + e.OldValue
+	case CertEventExpiry:
+		if e.Unexpected {
+			return fmt.Sprintf("过期时间提前：%s → %s", e.OldValue, e.NewValue)
+		}
+		return fmt.Sprintf("过期时间：%s → %s", e.OldValue, e.NewValue)
+	}
+	return e.Kind
+}
+
+// orNone 空值显示为"（无）"
+func orNone(value string) string {
+	if value == "" {
+		return "（无）"
+	}
+	return value
+}
+
+// recordCertSnapshot 与关注域名上次观察到的证书比较，记录变更事件并更新快照，返回本次检测到的变更
+// 首次记录时只保存快照和一条首次记录事件
+func (a *App) recordCertSnapshot(watchedID int64, info *CertificateInfo) ([]CertEvent, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+
+	current := newCertSnapshot(info)
+	tx, err := a.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var old CertSnapshot
+	var sanList sql.NullString
+	err = tx.QueryRow(`
+	SELECT COALESCE(serial_number, ''), COALESCE(issuer, ''), COALESCE(public_key_sha256, ''), san_list, COALESCE(not_after, '')
+	FROM cert_snapshots WHERE watched_domain_id = ?
+	`, watchedID).Scan(&old.SerialNumber, &old.Issuer, &old.PublicKeySHA256, &sanList, &old.NotAfter)
+
+	var events []CertEvent
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = tx.Exec(`
+		INSERT INTO cert_snapshots (watched_domain_id, serial_number, issuer, public_key_sha256, san_list, not_after, fingerprint_sha256)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, watchedID, current.SerialNumber, current.Issuer, current.PublicKeySHA256,
+			encodeStringList(current.SANs), current.NotAfter, current.FingerprintSHA256)
+		if err != nil {
+			return nil, fmt.Errorf("保存证书快照失败: %v", err)
+		}
+		first := CertEvent{Kind: CertEventFirstSeen, NewValue: current.SerialNumber, FingerprintSHA256: current.FingerprintSHA256}
+		if err := insertCertEvent(tx, watchedID, first); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, fmt.Errorf("读取证书快照失败: %v", err)
+	default:
+		old.SANs = decodeStringList(sanList.String)
+		events = diffSnapshots(old, current)
+		for _, event := range events {
+			if err := insertCertEvent(tx, watchedID, event); err != nil {
+				return nil, err
+			}
+		}
+		_, err = tx.Exec(`
+		UPDATE cert_snapshots SET serial_number = ?, issuer = ?, public_key_sha256 = ?, san_list = ?, not_after = ?,
+			fingerprint_sha256 = ?, checked_time = datetime('now', 'localtime')
+		WHERE watched_domain_id = ?
+		`, current.SerialNumber, current.Issuer, current.PublicKeySHA256, encodeStringList(current.SANs),
+			current.NotAfter, current.FingerprintSHA256, watchedID)
+		if err != nil {
+			return nil, fmt.Errorf("更新证书快照失败: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		fmt.Printf("✅ %s 的证书发生 %d 项变更\n", info.Domain, len(events))
+	}
+	return events, nil
+}
+
+// insertCertEvent 保存证书变更事件
+func insertCertEvent(tx *sql.Tx, watchedID int64, event CertEvent) error {
+	_, err := tx.Exec(`
+	INSERT INTO cert_events (watched_domain_id, kind, old_value, new_value, unexpected, fingerprint_sha256)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`, watchedID, event.Kind, event.OldValue, event.NewValue, event.Unexpected, event.FingerprintSHA256)
+	if err != nil {
+		return fmt.Errorf("保存证书变更事件失败: %v", err)
+	}
+	return nil
+}
+
+// GetDomainTimeline 获取关注域名的证书变更时间线（当前证书及全部变更事件）
+func (a *App) GetDomainTimeline(id int64) DomainTimelineResult {
+	if a.db == nil {
+		return DomainTimelineResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	wd, err := scanWatchedDomain(a.db.QueryRow("SELECT "+watchedDomainColumns+" FROM watched_domains WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return DomainTimelineResult{
+			Success: false,
+			Error:   "关注域名不存在",
+			Message: "关注域名不存在",
+		}
+	}
+	if err != nil {
+		return DomainTimelineResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+
+	result := DomainTimelineResult{Success: true, Domain: wd.target().String(), Events: []CertEvent{}}
+
+	var snapshot CertSnapshot
+	var sanList sql.NullString
+	err = a.db.QueryRow(`
+	SELECT COALESCE(serial_number, ''), COALESCE(issuer, ''), COALESCE(public_key_sha256, ''), san_list,
+	       COALESCE(not_after, ''), COALESCE(fingerprint_sha256, ''),
+	       strftime('%Y-%m-%d %H:%M:%S', first_seen_time), strftime('%Y-%m-%d %H:%M:%S', checked_time)
+	FROM cert_snapshots WHERE watched_domain_id = ?
+	`, id).Scan(&snapshot.SerialNumber, &snapshot.Issuer, &snapshot.PublicKeySHA256, &sanList,
+		&snapshot.NotAfter, &snapshot.FingerprintSHA256, &snapshot.FirstSeenTime, &snapshot.CheckedTime)
+	if err != nil && err != sql.ErrNoRows {
+		return DomainTimelineResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+	if err == nil {
+		snapshot.SANs = decodeStringList(sanList.String)
+		result.Snapshot = &snapshot
+	}
+
+	rows, err := a.db.Query(`
+	SELECT id, watched_domain_id, kind, COALESCE(old_value, ''), COALESCE(new_value, ''), COALESCE(unexpected, 0),
+	       COALESCE(fingerprint_sha256, ''), strftime('%Y-%m-%d %H:%M:%S', event_time)
+	FROM cert_events WHERE watched_domain_id = ?
+	ORDER BY event_time DESC, id DESC
+	`, id)
+	if err != nil {
+		return DomainTimelineResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event CertEvent
+		if err := rows.Scan(&event.ID, &event.WatchedDomainID, &event.Kind, &event.OldValue, &event.NewValue,
+			&event.Unexpected, &event.FingerprintSHA256, &event.EventTime); err != nil {
+			continue
+		}
+		event.Description = event.describe()
+		result.Events = append(result.Events, event)
+	}
+
+	result.Message = fmt.Sprintf("共 %d 条变更记录", len(result.Events))
+	return result
+}
+
+// pendingCertChangeNotifications 返回启用通知的关注域名尚未确认的意外证书变更（每个域名合并为一条）；
+// 只读取不修改，通知展示给用户后由前端调用 AcknowledgeCertEvents 确认
+func (a *App) pendingCertChangeNotifications(domains []WatchedDomain) ([]NotificationItem, error) {
+	rows, err := a.db.Query(`
+	SELECT e.id, e.watched_domain_id, e.kind, COALESCE(e.old_value, ''), COALESCE(e.new_value, ''), e.unexpected
+	FROM cert_events e JOIN watched_domains w ON w.id = e.watched_domain_id
+	WHERE e.unexpected = 1 AND e.notified = 0 AND w.notify_enabled = 1
+	ORDER BY e.event_time, e.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := make(map[int64][]string)
+	eventIDs := make(map[int64][]int64)
+	var order []int64
+	for rows.Next() {
+		var event CertEvent
+		if err := rows.Scan(&event.ID, &event.WatchedDomainID, &event.Kind, &event.OldValue, &event.NewValue, &event.Unexpected); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if _, ok := reasons[event.WatchedDomainID]; !ok {
+			order = append(order, event.WatchedDomainID)
+		}
+		reasons[event.WatchedDomainID] = append(reasons[event.WatchedDomainID], event.describe())
+		eventIDs[event.WatchedDomainID] = append(eventIDs[event.WatchedDomainID], event.ID)
+	}
+	rows.Close()
+	if len(order) == 0 {
+		return nil, nil
+	}
+
+	byID := make(map[int64]*WatchedDomain, len(domains))
+	for i := range domains {
+		byID[domains[i].ID] = &domains[i]
+	}
+
+	var items []NotificationItem
+	for _, id := range order {
+		wd := byID[id]
+		if wd == nil {
+			continue
+		}
+		item := NotificationItem{
+			ID:        wd.ID,
+			Domain:    wd.Domain,
+			Port:      wd.Port,
+			Nickname:  wd.Nickname,
+			Threshold: wd.NotifyThreshold,
+			Reason:    strings.Join(reasons[id], "; "),
+			Kind:      "cert_change",
+			EventIDs:  eventIDs[id],
+		}
+		if wd.CertInfo != nil {
+			item.DaysRemaining = wd.CertInfo.DaysRemaining
+			item.NotAfter = wd.CertInfo.NotAfter
+			item.Status = wd.CertInfo.Status
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// AcknowledgeCertEvents 将已展示给用户的证书变更通知标记为已通知，之后不再提醒
+func (a *App) AcknowledgeCertEvents(ids []int64) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	if _, err := a.db.Exec("UPDATE cert_events SET notified = 1 WHERE id IN ("+placeholders+")", args...); err != nil {
+		return fmt.Errorf("确认证书变更通知失败: %v", err)
+	}
+
+	fmt.Printf("✅ 确认证书变更通知: %d 条\n", len(ids))
+	return nil
+}
+
+// deleteCertHistory 删除关注域名的证书快照和变更事件
+func (a *App) deleteCertHistory(watchedID int64) {
+	a.db.Exec("DELETE FROM cert_snapshots WHERE watched_domain_id = ?", watchedID)
+	a.db.Exec("DELETE FROM cert_events WHERE watched_domain_id = ?", watchedID)
+}
@@ -0,0 +1,93 @@
+package main
+
+import "testing"
+
+func TestDiffSnapshots(t *testing.T) {
+	base := CertSnapshot{
+		SerialNumber:    "01",
+		Issuer:          "R10",
+		PublicKeySHA256: "key1",
+		SANs:            []string{"example.com", "www.example.com"},
+		NotAfter:        "2026-01-01 00:00:00",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(old, current *CertSnapshot)
+		kinds  []string
+		// 对应 kinds 的意外标记
+		unexpected []bool
+	}{
+		{"没有变化", func(old, s *CertSnapshot) {}, nil, nil},
+		{"常规续期", func(old, s *CertSnapshot) {
+			s.SerialNumber, s.PublicKeySHA256, s.NotAfter = "02", "key2", "2026-04-01 00:00:00"
+		}, []string{CertEventSerial, CertEventKey, CertEventExpiry}, []bool{false, false, false}},
+		{"更换CA", func(old, s *CertSnapshot) { s.Issuer = "Other CA" },
+			[]string{CertEventIssuer}, []bool{true}},
+		{"新增和移除SAN", func(old, s *CertSnapshot) { s.SANs = []string{"api.example.com", "example.com"} },
+			[]string{CertEventSANAdded, CertEventSANRemoved}, []bool{false, true}},
+		{"过期时间提前", func(old, s *CertSnapshot) { s.NotAfter = "2025-12-01 00:00:00" },
+			[]string{CertEventExpiry}, []bool{true}},
+		{"旧快照没有公钥摘要", func(old, s *CertSnapshot) { old.PublicKeySHA256, s.PublicKeySHA256 = "", "key2" }, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, current := base, base
+			current.SANs = append([]string(nil), base.SANs...)
+			tt.modify(&old, &current)
+
+			events := diffSnapshots(old, current)
+			if len(events) != len(tt.kinds) {
+				t.Fatalf("得到 %d 个事件 %+v，期望 %v", len(events), events, tt.kinds)
+			}
+			for i, event := range events {
+				if event.Kind != tt.kinds[i] || event.Unexpected != tt.unexpected[i] {
+					t.Errorf("事件 %d 为 %s（意外 %v），期望 %s（意外 %v）", i, event.Kind, event.Unexpected, tt.kinds[i], tt.unexpected[i])
+				}
+				if event.Description == "" {
+					t.Errorf("事件 %s 缺少说明", event.Kind)
+				}
+			}
+		})
+	}
+}
+
+func TestCertChangeNotificationsAcknowledge(t *testing.T) {
+	a := newTestApp(t)
+	result, err := a.db.Exec("INSERT INTO watched_domains (domain, notify_enabled) VALUES ('example.com', 1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := result.LastInsertId()
+	domains := []WatchedDomain{{ID: id, Domain: "example.com", Port: 443}}
+
+	cert := &CertificateInfo{SerialNumber: "01", Issuer: "R10", NotAfter: "2026-01-01 00:00:00", SANDomains: []string{"example.com"}}
+	if _, err := a.recordCertSnapshot(id, cert); err != nil {
+		t.Fatal(err)
+	}
+	changed := *cert
+	changed.SerialNumber, changed.Issuer = "02", "Other CA"
+	if _, err := a.recordCertSnapshot(id, &changed); err != nil {
+		t.Fatal(err)
+	}
+
+	// 多次查询（如多个窗口或后台轮询）在确认前都能得到通知
+	var items []NotificationItem
+	for i := 0; i < 2; i++ {
+		items, err = a.pendingCertChangeNotifications(domains)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || len(items[0].EventIDs) != 1 || items[0].Kind != "cert_change" {
+			t.Fatalf("第 %d 次查询得到 %+v，期望一条包含颁发者变化的通知", i+1, items)
+		}
+	}
+
+	if err := a.AcknowledgeCertEvents(items[0].EventIDs); err != nil {
+		t.Fatal(err)
+	}
+	items, err = a.pendingCertChangeNotifications(domains)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("确认后不应再通知，得到 %+v, %v", items, err)
+	}
+}
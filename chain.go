@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 证书在链中的角色
+const (
+	ChainRoleLeaf         = "leaf"
+	ChainRoleIntermediate = "intermediate"
+	ChainRoleRoot         = "root"
+)
+
+// ChainCertificate 证书链中的单个证书
+type ChainCertificate struct {
+	Position          int    `json:"position"` // 在服务器发送的链中的位置（0为叶子证书）
+	Role              string `json:"role"`     // "leaf", "intermediate", "root"
+	Subject           string `json:"subject"`
+	Issuer            string `json:"issuer"`
+	NotBefore         string `json:"notBefore"`
+	NotAfter          string `json:"notAfter"`
+	DaysRemaining     int    `json:"daysRemaining"`
+	KeyAlgorithm      string `json:"keyAlgorithm"` // 如 "RSA 2048"、"ECDSA P-256"
+	SerialNumber      string `json:"serialNumber"`
+	FingerprintSHA256 string `json:"fingerprintSha256"`
+}
+
+// chainAnalysis 证书链分析结果
+type chainAnalysis struct {
+	Chain          []ChainCertificate
+	Issues         []string
+	EarliestExpiry time.Time // 叶子证书和中间证书中最早的过期时间
+}
+
+// analyzeChain 整理服务器发送的证书链，并检查顺序错误、多余的根证书、缺少中间证书、中间证书早于叶子证书过期等问题
+func analyzeChain(certs []*x509.Certificate, roots *x509.CertPool, now time.Time) chainAnalysis {
+	var result chainAnalysis
+	if len(certs) == 0 {
+		return result
+	}
+
+	leaf := certs[0]
+	result.EarliestExpiry = leaf.NotAfter
+
+	for i, cert := range certs {
+		role := ChainRoleIntermediate
+		if i == 0 {
+			role = ChainRoleLeaf
+		} else if isSelfSigned(cert) {
+			role = ChainRoleRoot
+		}
+
+		result.Chain = append(result.Chain, ChainCertificate{
+			Position:          i,
+			Role:              role,
+			Subject:           cert.Subject.String(),
+			Issuer:            cert.Issuer.String(),
+			NotBefore:         cert.NotBefore.Format("2006-01-02 15:04:05"),
+			NotAfter:          cert.NotAfter.Format("2006-01-02 15:04:05"),
+			DaysRemaining:     int(cert.NotAfter.Sub(now).Hours() / 24),
+			KeyAlgorithm:      keyDescription(cert),
+			SerialNumber:      cert.SerialNumber.String(),
+			FingerprintSHA256: fingerprintSHA256(cert),
+		})
+
+		if i == 0 {
+			continue
+		}
+
+		if role == ChainRoleRoot {
+			result.Issues = append(result.Issues, fmt.Sprintf("服务器发送了不必要的根证书: %s", displayName(cert)))
+			continue
+		}
+
+		if cert.NotAfter.Before(leaf.NotAfter) {
+			result.Issues = append(result.Issues, fmt.Sprintf("中间证书 %s 将于 %s 过期，早于叶子证书",
+				displayName(cert), cert.NotAfter.Format("2006-01-02 15:04:05")))
+		}
+		if cert.NotAfter.Before(result.EarliestExpiry) {
+			result.EarliestExpiry = cert.NotAfter
+		}
+	}
+
+	// 每个证书都应由其后一个证书签发
+	for i := 0; i+1 < len(certs); i++ {
+		if !bytes.Equal(certs[i].RawIssuer, certs[i+1].RawSubject) {
+			result.Issues = append(result.Issues, fmt.Sprintf("证书链顺序错误: 第 %d 个证书 %s 不是由第 %d 个证书 %s 签发",
+				i+1, displayName(certs[i]), i+2, displayName(certs[i+1])))
+		}
+	}
+
+	// 链顶端的证书不是根证书时，应能直接由信任根签发，否则说明缺少中间证书
+	top := certs[len(certs)-1]
+	if !isSelfSigned(top) {
+		_, err := top.Verify(x509.VerifyOptions{
+			Roots:       roots,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if _, ok := err.(x509.UnknownAuthorityError); ok {
+			result.Issues = append(result.Issues, fmt.Sprintf("缺少中间证书: 未找到 %s 的颁发者 %s",
+				displayName(top), top.Issuer.String()))
+		}
+	}
+
+	return result
+}
+
+// isSelfSigned 判断证书是否自签名
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+		return false
+	}
+	return cert.CheckSignatureFrom(cert) == nil
+}
+
+// displayName 证书的简短展示名称，优先使用CN
+func displayName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
+
+// keyDescription 返回证书公钥算法和长度，如 "RSA 2048"、"ECDSA P-256"、"Ed25519"
+func keyDescription(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// fingerprintSHA256 返回证书DER编码的SHA-256指纹（大写十六进制，冒号分隔）
+func fingerprintSHA256(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return formatFingerprint(sum[:])
+}
+
+// formatFingerprint 将摘要格式化为 AA:BB:CC 形式
+func formatFingerprint(sum []byte) string {
+	encoded := strings.ToUpper(hex.EncodeToString(sum))
+	parts := make([]string, 0, len(sum))
+	for i := 0; i < len(encoded); i += 2 {
+		parts = append(parts, encoded[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
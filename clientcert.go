@@ -0,0 +1,496 @@
+package main
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// clientCertKeyFile 加密客户端证书私钥的密钥文件名（位于应用数据目录）
+const clientCertKeyFile = "client_cert.key"
+
+// ClientCertificate 客户端证书（用于要求双向TLS认证的目标），过期时间同样需要关注
+type ClientCertificate struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	Subject           string `json:"subject"`
+	Issuer            string `json:"issuer"`
+	NotBefore         string `json:"notBefore"`
+	NotAfter          string `json:"notAfter"`
+	DaysRemaining     int    `json:"daysRemaining"`
+	Status            string `json:"status"` // "safe", "warning", "danger", "expired"
+	FingerprintSHA256 string `json:"fingerprintSha256"`
+	NotifyEnabled     bool   `json:"notifyEnabled"`   // 是否启用过期通知
+	NotifyThreshold   int    `json:"notifyThreshold"` // 预警阈值（天数）
+	UsedBy            int    `json:"usedBy"`          // 使用该证书的关注域名数量
+	CreatedTime       string `json:"createdTime"`
+}
+
+// ClientCertificatesResult 客户端证书列表查询结果
+type ClientCertificatesResult struct {
+	Success      bool                `json:"success"`
+	Message      string              `json:"message"`
+	Total        int                 `json:"total"`
+	Certificates []ClientCertificate `json:"certificates"`
+	Error        string              `json:"error,omitempty"`
+}
+
+// ClientCertificateRequest 添加客户端证书请求：PEM（证书和未加密的私钥）或Base64编码的PKCS#12二选一
+type ClientCertificateRequest struct {
+	Name     string `json:"name"`
+	PEM      string `json:"pem,omitempty"`      // PEM格式的证书链和私钥
+	PKCS12   string `json:"pkcs12,omitempty"`   // Base64编码的PKCS#12（.p12/.pfx）文件内容
+	Password string `json:"password,omitempty"` // PKCS#12密码
+}
+
+// parseClientCertificate 解析客户端证书和私钥，返回以叶子证书开头的证书链
+func parseClientCertificate(req ClientCertificateRequest) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	var certs []*x509.Certificate
+	var key crypto.PrivateKey
+
+	if strings.TrimSpace(req.PKCS12) != "" {
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(req.PKCS12))
+		if err != nil {
+			return nil, nil, fmt.Errorf("PKCS#12内容不是有效的Base64: %v", err)
+		}
+		var leaf *x509.Certificate
+		var caCerts []*x509.Certificate
+		key, leaf, caCerts, err = pkcs12.DecodeChain(data, req.Password)
+		if err != nil {
+			return nil, nil, fmt.Errorf("解析PKCS#12失败（请检查密码）: %v", err)
+		}
+		certs = append([]*x509.Certificate{leaf}, caCerts...)
+	} else {
+		rest := []byte(req.PEM)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			switch {
+			case block.Type == "CERTIFICATE":
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, nil, fmt.Errorf("解析证书失败: %v", err)
+				}
+				certs = append(certs, cert)
+			case block.Type == "ENCRYPTED PRIVATE KEY" || block.Headers["Proc-Type"] != "":
+				return nil, nil, fmt.Errorf("不支持加密的PEM私钥，请先解密或改用PKCS#12")
+			case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+				parsed, err := parsePrivateKey(block.Bytes)
+				if err != nil {
+					return nil, nil, err
+				}
+				key = parsed
+			}
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("未找到客户端证书")
+	}
+	if key == nil {
+		return nil, nil, fmt.Errorf("未找到私钥")
+	}
+
+	// 将与私钥匹配的证书作为叶子证书放在最前
+	for i, cert := range certs {
+		if publicKeyMatches(cert.PublicKey, key) {
+			certs[0], certs[i] = certs[i], certs[0]
+			return certs, key, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("私钥与证书不匹配")
+}
+
+// parsePrivateKey 依次按PKCS#8、PKCS#1、SEC 1格式解析私钥
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("无法解析私钥（支持PKCS#8、PKCS#1和EC格式）")
+}
+
+// publicKeyMatches 判断证书公钥与私钥是否成对
+func publicKeyMatches(pub crypto.PublicKey, key crypto.PrivateKey) bool {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k.PublicKey.Equal(pub)
+	case *ecdsa.PrivateKey:
+		return k.PublicKey.Equal(pub)
+	case ed25519.PrivateKey:
+		return k.Public().(ed25519.PublicKey).Equal(pub)
+	}
+	return false
+}
+
+// clientCertSecretMu 避免首次使用时并发生成两个不同的密钥
+var clientCertSecretMu sync.Mutex
+
+// clientCertSecret 读取加密私钥使用的密钥，不存在时生成；密钥文件经 protectKey 保护（Windows使用DPAPI），
+// 文件损坏时返回错误且不覆盖，否则数据库中已加密的私钥将全部无法解密
+func clientCertSecret() ([]byte, error) {
+	clientCertSecretMu.Lock()
+	defer clientCertSecretMu.Unlock()
+
+	dir, err := appDataDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, clientCertKeyFile)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		secret, err := createClientCertSecret(path)
+		if !os.IsExist(err) {
+			return secret, err
+		}
+		// 其他进程同时生成了密钥，读取其结果
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+
+	secret, legacy, err := unprotectKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件 %s 失败: %v", path, err)
+	}
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("密钥文件 %s 已损坏（%d 字节），已保存的客户端证书私钥无法解密，请从备份恢复该文件", path, len(secret))
+	}
+	if legacy {
+		protectLegacyKey(path, secret)
+	}
+	return secret, nil
+}
+
+// createClientCertSecret 生成密钥并以独占方式创建密钥文件，文件已存在时返回 os.ErrExist
+func createClientCertSecret(path string) ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("生成密钥失败: %v", err)
+	}
+	data, err := protectKey(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("保存密钥文件失败: %v", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("保存密钥文件失败: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("保存密钥文件失败: %v", err)
+	}
+	fmt.Printf("✅ 已生成客户端证书私钥的加密密钥: %s（保护方式: %s）\n", path, keyProtection)
+	return secret, nil
+}
+
+// protectLegacyKey 将旧版本未加密保存的密钥改为受保护的形式（密钥本身不变），失败时保留原文件
+func protectLegacyKey(path string, secret []byte) {
+	data, err := protectKey(secret)
+	if err != nil {
+		fmt.Printf("❌ 保护密钥文件失败: %v\n", err)
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		fmt.Printf("❌ 保护密钥文件失败: %v\n", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		fmt.Printf("❌ 保护密钥文件失败: %v\n", err)
+		return
+	}
+	fmt.Printf("✅ 密钥文件已改为%s保护\n", keyProtection)
+}
+
+// encryptSecret 使用AES-256-GCM加密数据，结果为 nonce + 密文
+func encryptSecret(plaintext []byte) ([]byte, error) {
+	gcm, err := clientCertCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decryptSecret 解密 encryptSecret 的结果
+func decryptSecret(data []byte) ([]byte, error) {
+	gcm, err := clientCertCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("密文长度无效")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("解密失败（密钥文件可能已更换）: %v", err)
+	}
+	return plaintext, nil
+}
+
+// clientCertCipher 返回加密私钥使用的AES-GCM
+func clientCertCipher() (cipher.AEAD, error) {
+	secret, err := clientCertSecret()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// AddClientCertificate 添加客户端证书，私钥加密后保存到数据库
+func (a *App) AddClientCertificate(req ClientCertificateRequest) QueryResult {
+	if a.db == nil {
+		return QueryResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return QueryResult{
+			Success: false,
+			Error:   "名称不能为空",
+		}
+	}
+
+	certs, key, err := parseClientCertificate(req)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   fmt.Sprintf("不支持的私钥类型: %v", err),
+		}
+	}
+	encrypted, err := encryptSecret(keyDER)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   fmt.Sprintf("加密私钥失败: %v", err),
+		}
+	}
+
+	var certPEM strings.Builder
+	for _, cert := range certs {
+		pem.Encode(&certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+
+	leaf := certs[0]
+	_, err = a.db.Exec(`
+	INSERT INTO client_certificates (name, cert_pem, key_encrypted, subject, issuer, not_before, not_after)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, name, certPEM.String(), encrypted, leaf.Subject.String(), leaf.Issuer.String(),
+		leaf.NotBefore.Local().Format("2006-01-02 15:04:05"), leaf.NotAfter.Local().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   fmt.Sprintf("添加失败: %v", err),
+		}
+	}
+
+	return QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("添加客户端证书成功：%s，有效期至 %s", displayName(leaf), leaf.NotAfter.Local().Format("2006-01-02")),
+	}
+}
+
+// GetClientCertificates 获取客户端证书列表及其剩余有效期
+func (a *App) GetClientCertificates() ClientCertificatesResult {
+	if a.db == nil {
+		return ClientCertificatesResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	rows, err := a.db.Query(`
+	SELECT c.id, c.name, c.cert_pem, COALESCE(c.notify_enabled, 1), COALESCE(c.notify_threshold, 30),
+	       strftime('%Y-%m-%d %H:%M:%S', c.created_time) as created_time,
+	       (SELECT COUNT(*) FROM watched_domains w WHERE w.client_cert_id = c.id) as used_by
+	FROM client_certificates c
+	ORDER BY c.not_after ASC
+	`)
+	if err != nil {
+		return ClientCertificatesResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var list []ClientCertificate
+	for rows.Next() {
+		var cc ClientCertificate
+		var certPEM string
+		if err := rows.Scan(&cc.ID, &cc.Name, &certPEM, &cc.NotifyEnabled, &cc.NotifyThreshold,
+			&cc.CreatedTime, &cc.UsedBy); err != nil {
+			continue
+		}
+
+		certs, err := parseCAPEM(certPEM)
+		if err != nil {
+			continue
+		}
+		leaf := certs[0]
+		cc.Subject = leaf.Subject.String()
+		cc.Issuer = leaf.Issuer.String()
+		cc.NotBefore = leaf.NotBefore.Local().Format("2006-01-02 15:04:05")
+		cc.NotAfter = leaf.NotAfter.Local().Format("2006-01-02 15:04:05")
+		cc.DaysRemaining = int(leaf.NotAfter.Sub(now).Hours() / 24)
+		cc.Status = statusForDays(cc.DaysRemaining)
+		cc.FingerprintSHA256 = fingerprintSHA256(leaf)
+		list = append(list, cc)
+	}
+
+	return ClientCertificatesResult{
+		Success:      true,
+		Message:      fmt.Sprintf("查询到 %d 个客户端证书", len(list)),
+		Total:        len(list),
+		Certificates: list,
+	}
+}
+
+// RemoveClientCertificate 删除客户端证书，并解除关注域名上的引用
+func (a *App) RemoveClientCertificate(id int64) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	if _, err := a.db.Exec("UPDATE watched_domains SET client_cert_id = 0 WHERE client_cert_id = ?", id); err != nil {
+		return fmt.Errorf("解除关注域名引用失败: %v", err)
+	}
+
+	_, err := a.db.Exec("DELETE FROM client_certificates WHERE id = ?", id)
+	return err
+}
+
+// UpdateClientCertNotifySettings 更新客户端证书的过期通知设置
+func (a *App) UpdateClientCertNotifySettings(id int64, enabled bool, threshold int) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	// 阈值校验：1-365天
+	if threshold < 1 || threshold > 365 {
+		return fmt.Errorf("预警阈值必须在1-365天之间")
+	}
+
+	_, err := a.db.Exec("UPDATE client_certificates SET notify_enabled = ?, notify_threshold = ? WHERE id = ?",
+		enabled, threshold, id)
+	if err != nil {
+		return fmt.Errorf("更新通知设置失败: %v", err)
+	}
+
+	fmt.Printf("✅ 更新客户端证书通知设置成功: ID=%d, 启用=%v, 阈值=%d天\n", id, enabled, threshold)
+	return nil
+}
+
+// UpdateWatchedDomainClientCert 为关注域名指定探测时出示的客户端证书（0表示不出示）
+func (a *App) UpdateWatchedDomainClientCert(id int64, clientCertID int64) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	if clientCertID != 0 {
+		var count int
+		if err := a.db.QueryRow("SELECT COUNT(*) FROM client_certificates WHERE id = ?", clientCertID).Scan(&count); err != nil {
+			return fmt.Errorf("查询客户端证书失败: %v", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("客户端证书不存在")
+		}
+	}
+
+	_, err := a.db.Exec("UPDATE watched_domains SET client_cert_id = ? WHERE id = ?", clientCertID, id)
+	if err != nil {
+		return fmt.Errorf("更新客户端证书失败: %v", err)
+	}
+
+	fmt.Printf("✅ 更新关注域名客户端证书成功: ID=%d, 客户端证书=%d\n", id, clientCertID)
+	return nil
+}
+
+// clientCertificate 读取并解密客户端证书，id为0时返回nil
+func (a *App) clientCertificate(id int64) (*tls.Certificate, error) {
+	if id == 0 || a.db == nil {
+		return nil, nil
+	}
+
+	var certPEM string
+	var encrypted []byte
+	err := a.db.QueryRow("SELECT cert_pem, key_encrypted FROM client_certificates WHERE id = ?", id).Scan(&certPEM, &encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("读取客户端证书失败: %v", err)
+	}
+
+	certs, err := parseCAPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := decryptSecret(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("解密客户端证书私钥失败: %v", err)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyDER)
+	if err != nil {
+		return nil, fmt.Errorf("解析客户端证书私钥失败: %v", err)
+	}
+
+	cert := &tls.Certificate{PrivateKey: key, Leaf: certs[0]}
+	for _, c := range certs {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return cert, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// useTempAppDataDir 将应用数据目录指向临时目录，返回其中的密钥文件路径
+func useTempAppDataDir(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("仅在Linux上通过 XDG_CONFIG_HOME 重定向应用数据目录")
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	return filepath.Join(dir, "SSL-Cert-Checker", clientCertKeyFile)
+}
+
+func TestClientCertSecretCreate(t *testing.T) {
+	path := useTempAppDataDir(t)
+
+	secret, err := clientCertSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("未生成密钥文件: %v", err)
+	}
+	if len(secret) != 32 || info.Mode().Perm() != 0600 {
+		t.Fatalf("密钥长度为 %d，文件权限为 %v", len(secret), info.Mode().Perm())
+	}
+
+	again, err := clientCertSecret()
+	if err != nil || !bytes.Equal(secret, again) {
+		t.Fatalf("再次读取的密钥不一致: %v", err)
+	}
+
+	encrypted, err := encryptSecret([]byte("private key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err := decryptSecret(encrypted); err != nil || string(plaintext) != "private key" {
+		t.Fatalf("解密结果为 %q, %v", plaintext, err)
+	}
+}
+
+func TestClientCertSecretCorrupted(t *testing.T) {
+	path := useTempAppDataDir(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	truncated := bytes.Repeat([]byte{7}, 20)
+	if err := os.WriteFile(path, truncated, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := clientCertSecret(); err == nil || !strings.Contains(err.Error(), "已损坏") {
+		t.Fatalf("密钥文件损坏时应返回错误，得到 %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(data, truncated) {
+		t.Fatalf("损坏的密钥文件不应被覆盖: %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// SAN类型
+const (
+	SANTypeDNS = "dns"
+	SANTypeIP  = "ip"
+)
+
+// SANCoverage 单个SAN与查询名称的匹配情况
+type SANCoverage struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"` // "dns" 或 "ip"
+	Covers bool   `json:"covers"`
+	Reason string `json:"reason,omitempty"` // 相近但不匹配的原因（如通配符跨级）
+}
+
+// CoverageResult 证书SAN覆盖检查结果
+type CoverageResult struct {
+	Success           bool          `json:"success"`
+	Message           string        `json:"message"`
+	Name              string        `json:"name"`                // 检查的名称（指定了SNI时为SNI）
+	Covered           bool          `json:"covered"`             // 证书的SAN是否覆盖该名称
+	MatchedBy         string        `json:"matchedBy,omitempty"` // 覆盖该名称的SAN
+	SANs              []SANCoverage `json:"sans,omitempty"`
+	Subject           string        `json:"subject"`
+	SerialNumber      string        `json:"serialNumber"`
+	FingerprintSHA256 string        `json:"fingerprintSha256"`
+	NotAfter          string        `json:"notAfter"`
+	DaysRemaining     int           `json:"daysRemaining"`
+	Error             string        `json:"error,omitempty"`
+}
+
+// SharedCertGroup 共用同一证书的关注域名
+type SharedCertGroup struct {
+	FingerprintSHA256 string   `json:"fingerprintSha256"`
+	SerialNumber      string   `json:"serialNumber"`
+	Subject           string   `json:"subject"`
+	Issuer            string   `json:"issuer"`
+	NotAfter          string   `json:"notAfter"`
+	DaysRemaining     int      `json:"daysRemaining"`
+	Status            string   `json:"status"`
+	Wildcard          bool     `json:"wildcard"`            // 是否为通配符证书
+	Domains           []string `json:"domains"`             // 使用该证书的关注目标
+	Uncovered         []string `json:"uncovered,omitempty"` // 使用该证书但不在其SAN覆盖范围内的目标
+}
+
+// SharedCertReportResult 共用证书报告
+type SharedCertReportResult struct {
+	Success  bool              `json:"success"`
+	Message  string            `json:"message"`
+	Groups   []SharedCertGroup `json:"groups"`           // 被两个及以上目标共用的证书，按影响范围从大到小排列
+	Unshared int               `json:"unshared"`         // 仅被一个目标使用的证书数
+	Failed   []string          `json:"failed,omitempty"` // 查询失败的目标
+	Error    string            `json:"error,omitempty"`
+}
+
+// CheckCoverage 查询目标的证书，逐个检查SAN（DNS和IP）是否覆盖查询的名称
+func (a *App) CheckCoverage(domain string) CoverageResult {
+	result := a.checkCertificateInternal(domain, ProbeOptions{})
+	if !result.Success {
+		return CoverageResult{
+			Success: false,
+			Error:   result.Error,
+			Message: result.Message,
+		}
+	}
+
+	info := result.Data
+	name := info.SNI
+	if name == "" {
+		name = info.Domain
+	}
+	covered, matchedBy, sans := analyzeCoverage(name, info.SANDomains, info.SANIPs)
+
+	message := fmt.Sprintf("证书覆盖 %s（匹配 %s）", name, matchedBy)
+	if len(sans) == 0 {
+		message = "证书没有SAN，现代客户端不再使用CN匹配主机名"
+	} else if !covered {
+		message = fmt.Sprintf("证书的 %d 个SAN均不覆盖 %s", len(sans), name)
+	}
+
+	return CoverageResult{
+		Success:           true,
+		Message:           message,
+		Name:              name,
+		Covered:           covered,
+		MatchedBy:         matchedBy,
+		SANs:              sans,
+		Subject:           info.Subject,
+		SerialNumber:      info.SerialNumber,
+		FingerprintSHA256: info.FingerprintSHA256,
+		NotAfter:          info.NotAfter,
+		DaysRemaining:     info.DaysRemaining,
+	}
+}
+
+// analyzeCoverage 按RFC 6125检查SAN是否覆盖名称：IP地址只与IP类型的SAN比较，
+// 通配符只能作为最左侧的完整标签，且只匹配一级子域名
+func analyzeCoverage(name string, dnsNames, ipAddresses []string) (bool, string, []SANCoverage) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	ip := net.ParseIP(name)
+
+	var sans []SANCoverage
+	covered, matchedBy := false, ""
+	for _, dnsName := range dnsNames {
+		san := SANCoverage{Name: dnsName, Type: SANTypeDNS}
+		if ip != nil {
+			if net.ParseIP(dnsName) != nil && net.ParseIP(dnsName).Equal(ip) {
+				san.Reason = "IP地址写在DNS类型的SAN中，客户端不会将其用于匹配IP"
+			}
+		} else {
+			san.Covers, san.Reason = dnsNameCovers(strings.ToLower(strings.TrimSuffix(dnsName, ".")), name)
+		}
+		sans = append(sans, san)
+	}
+	for _, address := range ipAddresses {
+		san := SANCoverage{Name: address, Type: SANTypeIP}
+		san.Covers = ip != nil && net.ParseIP(address).Equal(ip)
+		sans = append(sans, san)
+	}
+
+	for _, san := range sans {
+		if san.Covers {
+			covered, matchedBy = true, san.Name
+			break
+		}
+	}
+	return covered, matchedBy, sans
+}
+
+// dnsNameCovers 判断DNS类型的SAN是否覆盖主机名，不覆盖但相近时返回原因
+func dnsNameCovers(pattern, host string) (bool, string) {
+	if pattern == host {
+		return true, ""
+	}
+	if !strings.Contains(pattern, "*") {
+		return false, ""
+	}
+
+	base, ok := strings.CutPrefix(pattern, "*.")
+	if !ok || strings.Contains(base, "*") {
+		if strings.HasSuffix(host, pattern[strings.LastIndex(pattern, "*")+1:]) {
+			return false, "通配符只能作为最左侧的完整标签，不支持部分通配符"
+		}
+		return false, ""
+	}
+	label, ok := strings.CutSuffix(host, "."+base)
+	if !ok && host != base {
+		return false, ""
+	}
+	if !strings.Contains(base, ".") {
+		return false, "通配符不能直接用于顶级域名"
+	}
+	if host == base {
+		return false, fmt.Sprintf("%s 不覆盖 %s 本身", pattern, base)
+	}
+	if strings.Contains(label, ".") {
+		return false, fmt.Sprintf("通配符只匹配一级子域名，%s 多了 %d 级", host, strings.Count(label, "."))
+	}
+	return true, ""
+}
+
+// GetSharedCertificateReport 刷新所有关注域名，按证书（SHA-256指纹）分组，列出被多个目标共用的证书，
+// 用于评估单个证书过期的影响范围
+func (a *App) GetSharedCertificateReport() SharedCertReportResult {
+	watched := a.GetWatchedDomains()
+	if !watched.Success {
+		return SharedCertReportResult{
+			Success: false,
+			Error:   watched.Error,
+			Message: watched.Message,
+		}
+	}
+
+	groups := make(map[string]*SharedCertGroup)
+	var order []string
+	var failed []string
+	for _, wd := range watched.Domains {
+		if wd.IsManual {
+			continue
+		}
+		target := wd.target()
+		if wd.CertInfo == nil {
+			failed = append(failed, target.String())
+			continue
+		}
+
+		info := wd.CertInfo
+		key := info.FingerprintSHA256
+		if key == "" {
+			key = info.Issuer + "/" + info.SerialNumber
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &SharedCertGroup{
+				FingerprintSHA256: info.FingerprintSHA256,
+				SerialNumber:      info.SerialNumber,
+				Subject:           info.Subject,
+				Issuer:            info.Issuer,
+				NotAfter:          info.NotAfter,
+				DaysRemaining:     info.DaysRemaining,
+				Status:            info.Status,
+			}
+			for _, name := range info.SANDomains {
+				if strings.HasPrefix(name, "*.") {
+					group.Wildcard = true
+				}
+			}
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		group.Domains = append(group.Domains, target.String())
+		if covered, _, _ := analyzeCoverage(wd.Options.verifyName(target), info.SANDomains, info.SANIPs); !covered {
+			group.Uncovered = append(group.Uncovered, target.String())
+		}
+	}
+
+	report := SharedCertReportResult{Success: true, Groups: []SharedCertGroup{}, Failed: failed}
+	for _, key := range order {
+		if len(groups[key].Domains) > 1 {
+			report.Groups = append(report.Groups, *groups[key])
+		} else {
+			report.Unshared++
+		}
+	}
+	// 共用目标越多越靠前，相同时先过期的靠前
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		if len(report.Groups[i].Domains) != len(report.Groups[j].Domains) {
+			return len(report.Groups[i].Domains) > len(report.Groups[j].Domains)
+		}
+		return report.Groups[i].DaysRemaining < report.Groups[j].DaysRemaining
+	})
+
+	report.Message = fmt.Sprintf("共 %d 个证书被多个目标共用，%d 个证书仅被一个目标使用", len(report.Groups), report.Unshared)
+	return report
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDNSNameCovers(t *testing.T) {
+	tests := []struct {
+		pattern string
+		host    string
+		covers  bool
+		reason  string
+	}{
+		{"example.com", "example.com", true, ""},
+		{"example.com", "www.example.com", false, ""},
+		{"*.example.com", "www.example.com", true, ""},
+		{"*.example.com", "example.com", false, "不覆盖 example.com 本身"},
+		{"*.example.com", "a.b.example.com", false, "多了 1 级"},
+		{"*.example.com", "www.example.org", false, ""},
+		{"*.example.com", "wwwexample.com", false, ""},
+		{"*.com", "example.com", false, "顶级域名"},
+		{"w*.example.com", "www.example.com", false, "部分通配符"},
+		{"www.*.com", "www.example.com", false, "最左侧的完整标签"},
+		{"*.*.example.com", "a.b.example.com", false, "部分通配符"},
+	}
+	for _, tt := range tests {
+		covers, reason := dnsNameCovers(tt.pattern, tt.host)
+		if covers != tt.covers || !strings.Contains(reason, tt.reason) || (tt.reason == "" && reason != "") {
+			t.Errorf("dnsNameCovers(%q, %q) = %v, %q，期望 %v, %q", tt.pattern, tt.host, covers, reason, tt.covers, tt.reason)
+		}
+	}
+}
+
+func TestAnalyzeCoverage(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		dnsNames    []string
+		ipAddresses []string
+		covered     bool
+		matchedBy   string
+		// 对应 SAN 的不匹配原因（包含即可）
+		reasons []string
+	}{
+		{"精确匹配", "www.example.com", []string{"example.com", "www.example.com"}, nil, true, "www.example.com", []string{"", ""}},
+		{"大小写和末尾的点", "WWW.Example.COM.", []string{"*.EXAMPLE.com"}, nil, true, "*.EXAMPLE.com", []string{""}},
+		{"通配符跨级", "a.b.example.com", []string{"*.example.com"}, nil, false, "", []string{"多了 1 级"}},
+		{"IP类型的SAN", "192.0.2.1", []string{"example.com"}, []string{"192.0.2.1"}, true, "192.0.2.1", []string{"", ""}},
+		{"IPv6写法不同", "2001:db8::1", nil, []string{"2001:0db8:0:0:0:0:0:1"}, true, "2001:0db8:0:0:0:0:0:1", []string{""}},
+		{"IP写在DNS类型的SAN中", "192.0.2.1", []string{"192.0.2.1"}, nil, false, "", []string{"DNS类型的SAN"}},
+		{"域名不匹配IP类型的SAN", "example.com", nil, []string{"192.0.2.1"}, false, "", []string{""}},
+		{"没有SAN", "example.com", nil, nil, false, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			covered, matchedBy, sans := analyzeCoverage(tt.host, tt.dnsNames, tt.ipAddresses)
+			if covered != tt.covered || matchedBy != tt.matchedBy {
+				t.Fatalf("analyzeCoverage = %v, %q，期望 %v, %q", covered, matchedBy, tt.covered, tt.matchedBy)
+			}
+			if len(sans) != len(tt.reasons) {
+				t.Fatalf("得到 %d 个SAN，期望 %d 个", len(sans), len(tt.reasons))
+			}
+			for i, san := range sans {
+				if !strings.Contains(san.Reason, tt.reasons[i]) {
+					t.Errorf("SAN %s 的原因为 %q，期望包含 %q", san.Name, san.Reason, tt.reasons[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxCRLSize CRL下载的大小上限
+const maxCRLSize = 20 << 20
+
+// CRLCheck 单个证书对照单个CRL分发点的检查结果
+type CRLCheck struct {
+	Certificate string `json:"certificate"`          // 被检查的证书（CN）
+	URL         string `json:"url"`                  // CRL分发点
+	Revoked     bool   `json:"revoked"`              // 证书序列号是否出现在CRL中
+	RevokedAt   string `json:"revokedAt,omitempty"`  // 吊销时间
+	Reason      string `json:"reason,omitempty"`     // 吊销原因
+	NextUpdate  string `json:"nextUpdate,omitempty"` // CRL下次更新时间
+	Stale       bool   `json:"stale"`                // CRL是否已过期（超过nextUpdate）
+	Cached      bool   `json:"cached"`               // 是否来自本地缓存
+	Error       string `json:"error,omitempty"`      // 获取或解析失败原因
+}
+
+// CRLCacheEntry 本地缓存的CRL
+type CRLCacheEntry struct {
+	ID               int64  `json:"id"`
+	Issuer           string `json:"issuer"`
+	URL              string `json:"url"`
+	ThisUpdate       string `json:"thisUpdate"`
+	NextUpdate       string `json:"nextUpdate"`
+	RevokedCount     int    `json:"revokedCount"`
+	FetchedTime      string `json:"fetchedTime"`
+	Stale            bool   `json:"stale"`            // 是否已超过nextUpdate
+	HoursUntilUpdate int    `json:"hoursUntilUpdate"` // 距nextUpdate的小时数（负数表示已过期）
+}
+
+// CRLStatusResult CRL缓存查询结果
+type CRLStatusResult struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Total   int             `json:"total"`
+	Stale   int             `json:"stale"` // 已过期的CRL数量
+	CRLs    []CRLCacheEntry `json:"crls"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// checkCRLs 从叶子证书和中间证书的CRL分发点获取CRL，检查证书序列号是否被吊销
+// leafIssuer 为叶子证书的颁发者（可能来自AIA下载），中间证书的颁发者取链中的下一个证书
+func (a *App) checkCRLs(ctx context.Context, info *CertificateInfo, certs []*x509.Certificate, leafIssuer *x509.Certificate, opts ProbeOptions) {
+	now := time.Now()
+	for i, cert := range certs {
+		if isSelfSigned(cert) {
+			continue
+		}
+
+		issuer := leafIssuer
+		if i > 0 {
+			issuer = nil
+			if i+1 < len(certs) && cert.CheckSignatureFrom(certs[i+1]) == nil {
+				issuer = certs[i+1]
+			}
+		}
+
+		for _, url := range cert.CRLDistributionPoints {
+			if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+				continue
+			}
+
+			check := CRLCheck{Certificate: displayName(cert), URL: url}
+			crl, cached, err := a.loadCRL(ctx, cert, url, issuer, now, opts)
+			if err != nil {
+				check.Error = err.Error()
+				info.CRL = append(info.CRL, check)
+				continue
+			}
+
+			check.Cached = cached
+			check.NextUpdate = crl.NextUpdate.Format("2006-01-02 15:04:05")
+			check.Stale = !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate)
+			if check.Stale {
+				info.Warnings = append(info.Warnings, fmt.Sprintf("CRL %s 已过期（下次更新时间 %s）", url, check.NextUpdate))
+			}
+
+			for _, entry := range crl.RevokedCertificateEntries {
+				if entry.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+					continue
+				}
+				check.Revoked = true
+				check.RevokedAt = entry.RevocationTime.Format("2006-01-02 15:04:05")
+				check.Reason = revocationReasonText(entry.ReasonCode)
+				markRevoked(info, fmt.Sprintf("证书 %s 已被吊销（CRL）: %s，原因: %s",
+					check.Certificate, check.RevokedAt, check.Reason))
+				break
+			}
+			info.CRL = append(info.CRL, check)
+		}
+	}
+}
+
+// loadCRL 获取证书的CRL：缓存未过期时直接使用，否则重新下载并更新缓存
+// 下载失败但有过期缓存时仍使用缓存（调用方会标记为过期）
+func (a *App) loadCRL(ctx context.Context, cert *x509.Certificate, url string, issuer *x509.Certificate, now time.Time, opts ProbeOptions) (*x509.RevocationList, bool, error) {
+	cached, err := a.cachedCRL(cert.Issuer.String(), url)
+	if err == nil && cached != nil && (cached.NextUpdate.IsZero() || now.Before(cached.NextUpdate)) {
+		return cached, true, nil
+	}
+
+	crl, raw, err := a.fetchCRL(ctx, url, cert, issuer, opts)
+	if err != nil {
+		if cached != nil {
+			return cached, true, nil
+		}
+		return nil, false, err
+	}
+
+	if err := a.saveCRL(cert.Issuer.String(), url, raw, crl); err != nil {
+		fmt.Printf("❌ 缓存CRL失败 %s: %v\n", url, err)
+	}
+	return crl, false, nil
+}
+
+// fetchCRL 下载并解析CRL（DER或PEM格式），校验颁发者，有颁发者证书时同时校验签名
+func (a *App) fetchCRL(ctx context.Context, url string, cert, issuer *x509.Certificate, opts ProbeOptions) (*x509.RevocationList, []byte, error) {
+	resp, err := httpGet(ctx, a.httpFetcher(opts), url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("下载CRL失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("下载CRL失败: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCRLSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取CRL失败: %v", err)
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析CRL失败: %v", err)
+	}
+	if crl.Issuer.String() != cert.Issuer.String() {
+		return nil, nil, fmt.Errorf("CRL颁发者 %s 与证书颁发者 %s 不一致", crl.Issuer, cert.Issuer)
+	}
+	if issuer != nil {
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			return nil, nil, fmt.Errorf("CRL签名校验失败: %v", err)
+		}
+	}
+
+	return crl, data, nil
+}
+
+// cachedCRL 读取缓存的CRL，不存在时返回nil
+func (a *App) cachedCRL(issuer, url string) (*x509.RevocationList, error) {
+	if a.db == nil {
+		return nil, nil
+	}
+
+	var raw []byte
+	err := a.db.QueryRow("SELECT der FROM crl_cache WHERE issuer = ? AND url = ?", issuer, url).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseRevocationList(raw)
+}
+
+// saveCRL 保存或更新CRL缓存
+func (a *App) saveCRL(issuer, url string, raw []byte, crl *x509.RevocationList) error {
+	if a.db == nil {
+		return nil
+	}
+
+	var nextUpdate interface{}
+	if !crl.NextUpdate.IsZero() {
+		nextUpdate = crl.NextUpdate.Local().Format("2006-01-02 15:04:05")
+	}
+
+	_, err := a.db.Exec(`
+	INSERT INTO crl_cache (issuer, url, der, this_update, next_update, revoked_count, fetched_time)
+	VALUES (?, ?, ?, ?, ?, ?, datetime('now', 'localtime'))
+	ON CONFLICT(issuer, url) DO UPDATE SET
+		der = excluded.der,
+		this_update = excluded.this_update,
+		next_update = excluded.next_update,
+		revoked_count = excluded.revoked_count,
+		fetched_time = excluded.fetched_time
+	`, issuer, url, raw, crl.ThisUpdate.Local().Format("2006-01-02 15:04:05"), nextUpdate, len(crl.RevokedCertificateEntries))
+	return err
+}
+
+// GetCRLStatus 获取本地缓存的CRL列表及其新鲜度
+func (a *App) GetCRLStatus() CRLStatusResult {
+	if a.db == nil {
+		return CRLStatusResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	rows, err := a.db.Query(`
+	SELECT id, issuer, url,
+	       strftime('%Y-%m-%d %H:%M:%S', this_update) as this_update,
+	       strftime('%Y-%m-%d %H:%M:%S', next_update) as next_update,
+	       revoked_count,
+	       strftime('%Y-%m-%d %H:%M:%S', fetched_time) as fetched_time
+	FROM crl_cache
+	ORDER BY next_update ASC
+	`)
+	if err != nil {
+		return CRLStatusResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var entries []CRLCacheEntry
+	stale := 0
+	for rows.Next() {
+		var entry CRLCacheEntry
+		var thisUpdate, nextUpdate, fetchedTime sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.Issuer, &entry.URL, &thisUpdate, &nextUpdate,
+			&entry.RevokedCount, &fetchedTime); err != nil {
+			continue
+		}
+		entry.ThisUpdate = thisUpdate.String
+		entry.NextUpdate = nextUpdate.String
+		entry.FetchedTime = fetchedTime.String
+
+		if next, err := time.ParseInLocation("2006-01-02 15:04:05", entry.NextUpdate, time.Local); err == nil {
+			entry.HoursUntilUpdate = int(next.Sub(now).Hours())
+			entry.Stale = now.After(next)
+		}
+		if entry.Stale {
+			stale++
+		}
+		entries = append(entries, entry)
+	}
+
+	return CRLStatusResult{
+		Success: true,
+		Message: fmt.Sprintf("缓存了 %d 个CRL，其中 %d 个已过期", len(entries), stale),
+		Total:   len(entries),
+		Stale:   stale,
+		CRLs:    entries,
+	}
+}
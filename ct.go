@@ -0,0 +1,376 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	_ "embed"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// SCT来源
+const (
+	SCTSourceEmbedded = "embedded" // 证书内嵌（X.509扩展）
+	SCTSourceTLS      = "tls"      // TLS握手扩展
+	SCTSourceOCSP     = "ocsp"     // OCSP响应扩展
+)
+
+// ctLogListFile 用户更新后的CT日志列表文件名（位于应用数据目录）
+const ctLogListFile = "ct_logs.json"
+
+var (
+	// oidEmbeddedSCTList 证书内嵌SCT列表扩展
+	oidEmbeddedSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+	// oidOCSPSCTList OCSP响应中的SCT列表扩展
+	oidOCSPSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 5}
+)
+
+// bundledCTLogs 随程序发布的CT日志列表（格式兼容 log_list.json v3）
+//
+//go:embed ctlogs.json
+var bundledCTLogs []byte
+
+// SCTInfo 单个签名证书时间戳（SCT）
+type SCTInfo struct {
+	LogID       string `json:"logId"`                 // 日志ID（Base64）
+	LogName     string `json:"logName,omitempty"`     // 日志名称（日志列表中已知时）
+	LogOperator string `json:"logOperator,omitempty"` // 日志运营方
+	Timestamp   string `json:"timestamp"`             // 日志签发时间
+	Source      string `json:"source"`                // "embedded", "tls", "ocsp"
+}
+
+// CTInfo 证书透明度（CT）检查结果
+type CTInfo struct {
+	SCTs      []SCTInfo `json:"scts,omitempty"`
+	Count     int       `json:"count"`            // SCT数量（已去除重复）
+	PublicCA  bool      `json:"publicCa"`         // 证书是否由公共CA签发（系统根证书可验证）
+	Required  int       `json:"required"`         // 公共CA证书要求的最少SCT数量
+	Compliant bool      `json:"compliant"`        // 是否满足要求（非公共CA证书不要求）
+	Errors    []string  `json:"errors,omitempty"` // SCT解析错误
+}
+
+// CTLogListResult CT日志列表更新结果
+type CTLogListResult struct {
+	Success  bool   `json:"success"`
+	Message  string `json:"message"`
+	Version  string `json:"version"`
+	LogCount int    `json:"logCount"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ctLog 日志列表中的单个日志
+type ctLog struct {
+	Name     string
+	Operator string
+}
+
+// ctLogEntry 日志列表中的日志（RFC 6962日志和Static CT API日志的公共字段）
+type ctLogEntry struct {
+	Description string `json:"description"`
+	LogID       string `json:"log_id"`
+}
+
+// ctLogList 日志列表（log_list.json v3 的子集）
+type ctLogList struct {
+	Version   string `json:"version"`
+	Operators []struct {
+		Name      string       `json:"name"`
+		Logs      []ctLogEntry `json:"logs"`
+		TiledLogs []ctLogEntry `json:"tiled_logs"`
+	} `json:"operators"`
+}
+
+var (
+	ctLogsMu      sync.Mutex
+	ctLogsLoaded  bool
+	ctLogsVersion string
+	ctLogs        map[string]ctLog
+)
+
+// parseCTLogList 解析日志列表，返回版本和以日志ID为键的日志信息
+func parseCTLogList(data []byte) (string, map[string]ctLog, error) {
+	var list ctLogList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return "", nil, fmt.Errorf("解析CT日志列表失败: %v", err)
+	}
+
+	logs := make(map[string]ctLog)
+	for _, operator := range list.Operators {
+		for _, log := range append(operator.Logs, operator.TiledLogs...) {
+			if _, err := base64.StdEncoding.DecodeString(log.LogID); err != nil {
+				return "", nil, fmt.Errorf("无效的日志ID %s: %v", log.LogID, err)
+			}
+			logs[log.LogID] = ctLog{Name: log.Description, Operator: operator.Name}
+		}
+	}
+	return list.Version, logs, nil
+}
+
+// knownCTLogs 返回已知的CT日志：优先使用应用数据目录中更新过的列表，否则使用随程序发布的列表
+func knownCTLogs() map[string]ctLog {
+	ctLogsMu.Lock()
+	defer ctLogsMu.Unlock()
+
+	if ctLogsLoaded {
+		return ctLogs
+	}
+	ctLogsLoaded = true
+
+	// 只读取已存在的列表，不创建应用数据目录
+	if dir, err := appDataPath(); err == nil {
+		path := filepath.Join(dir, ctLogListFile)
+		if _, err := os.Stat(path); err == nil {
+			data, err := os.ReadFile(path)
+			if err == nil {
+				var version string
+				var logs map[string]ctLog
+				if version, logs, err = parseCTLogList(data); err == nil {
+					ctLogsVersion, ctLogs = version, logs
+					return ctLogs
+				}
+			}
+			fmt.Printf("❌ 读取已更新的CT日志列表失败，使用内置列表: %v\n", err)
+		}
+	}
+
+	version, logs, err := parseCTLogList(bundledCTLogs)
+	if err != nil {
+		fmt.Printf("❌ 内置CT日志列表无效: %v\n", err)
+		logs = map[string]ctLog{}
+	}
+	ctLogsVersion, ctLogs = version, logs
+	return ctLogs
+}
+
+// UpdateCTLogList 用新的日志列表（log_list.json v3 格式）替换内置列表，保存到应用数据目录
+func (a *App) UpdateCTLogList(jsonText string) CTLogListResult {
+	version, logs, err := parseCTLogList([]byte(jsonText))
+	if err != nil {
+		return CTLogListResult{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	dir, err := appDataDir()
+	if err != nil {
+		return CTLogListResult{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ctLogListFile), []byte(jsonText), 0644); err != nil {
+		return CTLogListResult{
+			Success: false,
+			Error:   fmt.Sprintf("保存CT日志列表失败: %v", err),
+		}
+	}
+
+	ctLogsMu.Lock()
+	ctLogsLoaded, ctLogsVersion, ctLogs = true, version, logs
+	ctLogsMu.Unlock()
+
+	return CTLogListResult{
+		Success:  true,
+		Message:  fmt.Sprintf("CT日志列表已更新，共 %d 个日志", len(logs)),
+		Version:  version,
+		LogCount: len(logs),
+	}
+}
+
+// GetCTLogList 获取当前使用的CT日志列表版本和日志数量
+func (a *App) GetCTLogList() CTLogListResult {
+	logs := knownCTLogs()
+
+	ctLogsMu.Lock()
+	version := ctLogsVersion
+	ctLogsMu.Unlock()
+
+	return CTLogListResult{
+		Success:  true,
+		Message:  fmt.Sprintf("当前CT日志列表共 %d 个日志", len(logs)),
+		Version:  version,
+		LogCount: len(logs),
+	}
+}
+
+// checkCT 收集叶子证书内嵌、TLS扩展和OCSP响应中的SCT，并检查公共CA证书的SCT数量是否满足要求
+func checkCT(info *CertificateInfo, state *tls.ConnectionState) {
+	leaf := state.PeerCertificates[0]
+	result := &CTInfo{}
+	info.CT = result
+
+	for _, ext := range leaf.Extensions {
+		if !ext.Id.Equal(oidEmbeddedSCTList) {
+			continue
+		}
+		var list []byte
+		if _, err := asn1.Unmarshal(ext.Value, &list); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("解析内嵌SCT失败: %v", err))
+			continue
+		}
+		scts, err := parseSCTList(list, SCTSourceEmbedded)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		result.SCTs = append(result.SCTs, scts...)
+	}
+
+	for _, raw := range state.SignedCertificateTimestamps {
+		sct, err := parseSCT(raw, SCTSourceTLS)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.SCTs = append(result.SCTs, sct)
+	}
+
+	if info.OCSP != nil && len(info.OCSP.sctList) > 0 {
+		scts, err := parseSCTList(info.OCSP.sctList, SCTSourceOCSP)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		result.SCTs = append(result.SCTs, scts...)
+	}
+
+	result.SCTs = dedupSCTs(result.SCTs)
+	result.Count = len(result.SCTs)
+	result.PublicCA = isPublicCA(state.PeerCertificates)
+	result.Required = requiredSCTs(leaf, embeddedOnly(result.SCTs))
+	logs := distinctSCTLogs(result.SCTs)
+	result.Compliant = !result.PublicCA || logs >= result.Required
+	if !result.Compliant {
+		info.Warnings = append(info.Warnings,
+			fmt.Sprintf("公共CA签发的证书仅有 %d 个不同CT日志的SCT（至少需要 %d 个），可能未完整记录到CT日志", logs, result.Required))
+	}
+}
+
+// dedupSCTs 去除通过多个途径重复提供的SCT（日志ID和时间戳都相同），保留最先出现的来源
+func dedupSCTs(scts []SCTInfo) []SCTInfo {
+	seen := make(map[string]bool)
+	result := scts[:0]
+	for _, sct := range scts {
+		key := sct.LogID + "|" + sct.Timestamp
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, sct)
+	}
+	return result
+}
+
+// embeddedOnly 判断SCT是否都内嵌在证书中
+func embeddedOnly(scts []SCTInfo) bool {
+	for _, sct := range scts {
+		if sct.Source != SCTSourceEmbedded {
+			return false
+		}
+	}
+	return true
+}
+
+// distinctSCTLogs 统计SCT来自的不同CT日志数量，同一日志的多个SCT只计一次
+func distinctSCTLogs(scts []SCTInfo) int {
+	logs := make(map[string]bool)
+	for _, sct := range scts {
+		logs[sct.LogID] = true
+	}
+	return len(logs)
+}
+
+// requiredSCTs 公共CA证书要求的最少SCT数量：仅内嵌SCT时按证书有效期（180天以内2个，否则3个），
+// 通过TLS扩展或OCSP提供SCT时为2个
+func requiredSCTs(leaf *x509.Certificate, embeddedOnly bool) int {
+	if embeddedOnly && leaf.NotAfter.Sub(leaf.NotBefore) > 180*24*time.Hour {
+		return 3
+	}
+	return 2
+}
+
+// isPublicCA 判断证书链能否由系统根证书验证（即由公共CA签发）
+func isPublicCA(certs []*x509.Certificate) bool {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Intermediates: intermediates,
+		CurrentTime:   certs[0].NotBefore.Add(time.Second),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// ocspSCTList 取出OCSP响应中的SCT列表扩展
+func ocspSCTList(resp *ocsp.Response) []byte {
+	for _, ext := range resp.Extensions {
+		if !ext.Id.Equal(oidOCSPSCTList) {
+			continue
+		}
+		var list []byte
+		if _, err := asn1.Unmarshal(ext.Value, &list); err == nil {
+			return list
+		}
+	}
+	return nil
+}
+
+// parseSCTList 解析TLS编码的SignedCertificateTimestampList（RFC 6962 3.3）
+func parseSCTList(data []byte, source string) ([]SCTInfo, error) {
+	if len(data) < 2 || int(binary.BigEndian.Uint16(data)) != len(data)-2 {
+		return nil, fmt.Errorf("SCT列表长度无效")
+	}
+	data = data[2:]
+
+	var scts []SCTInfo
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return scts, fmt.Errorf("SCT列表被截断")
+		}
+		n := int(binary.BigEndian.Uint16(data))
+		if len(data) < 2+n {
+			return scts, fmt.Errorf("SCT列表被截断")
+		}
+		sct, err := parseSCT(data[2:2+n], source)
+		if err != nil {
+			return scts, err
+		}
+		scts = append(scts, sct)
+		data = data[2+n:]
+	}
+	return scts, nil
+}
+
+// parseSCT 解析单个SCT（v1），只取日志ID和时间戳
+func parseSCT(data []byte, source string) (SCTInfo, error) {
+	// version(1) + log_id(32) + timestamp(8)
+	if len(data) < 41 {
+		return SCTInfo{}, fmt.Errorf("SCT长度无效")
+	}
+	if data[0] != 0 {
+		return SCTInfo{}, fmt.Errorf("不支持的SCT版本: %d", data[0])
+	}
+
+	logID := base64.StdEncoding.EncodeToString(data[1:33])
+	millis := binary.BigEndian.Uint64(data[33:41])
+	sct := SCTInfo{
+		LogID:     logID,
+		Timestamp: time.UnixMilli(int64(millis)).Format("2006-01-02 15:04:05"),
+		Source:    source,
+	}
+	if log, ok := knownCTLogs()[logID]; ok {
+		sct.LogName = log.Name
+		sct.LogOperator = log.Operator
+	}
+	return sct, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// buildSCT 构造一个v1 SCT（日志ID + 时间戳，扩展和签名为空）
+func buildSCT(logID byte, millis uint64) []byte {
+	sct := []byte{0}
+	for i := 0; i < 32; i++ {
+		sct = append(sct, logID)
+	}
+	sct = binary.BigEndian.AppendUint64(sct, millis)
+	// extensions(2) + hash/signature algorithm(2) + signature(2)
+	return append(sct, 0, 0, 4, 3, 0, 0)
+}
+
+// buildSCTList 将SCT编码为SignedCertificateTimestampList
+func buildSCTList(scts ...[]byte) []byte {
+	var body []byte
+	for _, sct := range scts {
+		body = binary.BigEndian.AppendUint16(body, uint16(len(sct)))
+		body = append(body, sct...)
+	}
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(body))), body...)
+}
+
+func TestParseSCTList(t *testing.T) {
+	millis := uint64(time.Date(2025, 3, 1, 8, 0, 0, 0, time.Local).UnixMilli())
+	two := buildSCTList(buildSCT(1, millis), buildSCT(2, millis))
+	truncated := append([]byte(nil), two[:len(two)-3]...)
+	truncated[0], truncated[1] = 0, byte(len(truncated)-2)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		count   int
+		wantErr bool
+	}{
+		{"两个SCT", two, 2, false},
+		{"空列表", buildSCTList(), 0, false},
+		{"长度不符", append(buildSCTList(buildSCT(1, millis)), 0), 0, true},
+		{"列表被截断", truncated, 1, true},
+		{"SCT过短", buildSCTList([]byte{0, 1, 2}), 0, true},
+		{"不支持的版本", buildSCTList(append([]byte{1}, buildSCT(1, millis)[1:]...)), 0, true},
+		{"数据过短", []byte{0}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scts, err := parseSCTList(tt.data, SCTSourceTLS)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(scts) != tt.count {
+				t.Fatalf("解析出 %d 个SCT，期望 %d 个", len(scts), tt.count)
+			}
+		})
+	}
+
+	scts, _ := parseSCTList(two, SCTSourceEmbedded)
+	wantID := base64.StdEncoding.EncodeToString(buildSCT(1, millis)[1:33])
+	if scts[0].LogID != wantID || scts[0].Source != SCTSourceEmbedded || scts[0].Timestamp != "2025-03-01 08:00:00" {
+		t.Fatalf("SCT内容不正确: %+v", scts[0])
+	}
+}
+
+func TestDedupSCTs(t *testing.T) {
+	scts := []SCTInfo{
+		{LogID: "A", Timestamp: "2025-03-01 08:00:00", Source: SCTSourceEmbedded},
+		{LogID: "A", Timestamp: "2025-03-01 08:00:00", Source: SCTSourceTLS},
+		{LogID: "A", Timestamp: "2025-03-02 08:00:00", Source: SCTSourceOCSP},
+		{LogID: "B", Timestamp: "2025-03-01 08:00:00", Source: SCTSourceOCSP},
+	}
+	result := dedupSCTs(scts)
+	if len(result) != 3 {
+		t.Fatalf("去重后 %d 个SCT，期望 3 个", len(result))
+	}
+	if result[0].Source != SCTSourceEmbedded {
+		t.Fatalf("应保留最先出现的来源，得到 %s", result[0].Source)
+	}
+	if n := distinctSCTLogs(result); n != 2 {
+		t.Fatalf("不同日志数 %d，期望 2", n)
+	}
+	if embeddedOnly(result) || !embeddedOnly(result[:1]) {
+		t.Fatal("embeddedOnly 结果不正确")
+	}
+}
+
+func TestBundledCTLogs(t *testing.T) {
+	version, logs, err := parseCTLogList(bundledCTLogs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version == "" || len(logs) == 0 {
+		t.Fatalf("内置CT日志列表为空（版本 %q，%d 个日志）", version, len(logs))
+	}
+
+	operators := make(map[string]bool)
+	for _, log := range logs {
+		operators[log.Operator] = true
+	}
+	for _, operator := range []string{"Google", "Cloudflare", "DigiCert", "Sectigo", "Let's Encrypt"} {
+		if !operators[operator] {
+			t.Errorf("内置CT日志列表缺少 %s 的日志", operator)
+		}
+	}
+
+	// Static CT API日志位于 tiled_logs
+	if log, ok := logs["ppWirZJtb5lujvxJAUJX2LvwRqfWJYm4jcLXh2x45S8="]; !ok || log.Operator != "Let's Encrypt" {
+		t.Errorf("未读取 tiled_logs 中的日志: %+v", log)
+	}
+}
+
+func TestKnownCTLogsDoesNotCreateDataDir(t *testing.T) {
+	path := useTempAppDataDir(t)
+	ctLogsMu.Lock()
+	ctLogsLoaded = false
+	ctLogsMu.Unlock()
+	t.Cleanup(func() {
+		ctLogsMu.Lock()
+		ctLogsLoaded = false
+		ctLogsMu.Unlock()
+	})
+
+	if logs := knownCTLogs(); len(logs) == 0 {
+		t.Fatal("没有更新过的列表时应使用内置CT日志列表")
+	}
+	if _, err := os.Stat(filepath.Dir(path)); !os.IsNotExist(err) {
+		t.Fatalf("读取CT日志列表不应创建应用数据目录: %v", err)
+	}
+}
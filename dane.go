@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TLSA证书用途（RFC 7218）
+const (
+	tlsaUsagePKIXTA = 0 // CA证书约束，且需通过PKIX校验
+	tlsaUsagePKIXEE = 1 // 服务器证书约束，且需通过PKIX校验
+	tlsaUsageDANETA = 2 // 以匹配的证书作为信任锚
+	tlsaUsageDANEEE = 3 // 直接匹配服务器证书
+)
+
+// tlsaUsageNames、tlsaSelectorNames、tlsaMatchingNames TLSA字段的助记名称
+var (
+	tlsaUsageNames    = []string{"PKIX-TA", "PKIX-EE", "DANE-TA", "DANE-EE"}
+	tlsaSelectorNames = []string{"Cert", "SPKI"}
+	tlsaMatchingNames = []string{"Full", "SHA2-256", "SHA2-512"}
+)
+
+// TLSARecordResult 单条TLSA记录的校验结果
+type TLSARecordResult struct {
+	Usage        int    `json:"usage"`        // 0-3
+	Selector     int    `json:"selector"`     // 0: 完整证书，1: 公钥（SPKI）
+	MatchingType int    `json:"matchingType"` // 0: 完全匹配，1: SHA-256，2: SHA-512
+	Data         string `json:"data"`         // 关联数据（十六进制）
+	Description  string `json:"description"`  // 助记形式，如 "DANE-EE SPKI SHA2-256"
+	Pass         bool   `json:"pass"`
+	Matched      string `json:"matched,omitempty"` // 匹配的证书（CN）
+	Reason       string `json:"reason,omitempty"`  // 未通过的原因
+}
+
+// DANEInfo DANE/TLSA校验结果（未校验DNSSEC，仅比对记录与证书链）
+type DANEInfo struct {
+	Name    string             `json:"name"`              // 查询的TLSA记录名，如 _25._tcp.mail.example.com
+	Records []TLSARecordResult `json:"records,omitempty"` // 各条记录的校验结果
+	Valid   bool               `json:"valid"`             // 是否至少有一条记录通过
+	Error   string             `json:"error,omitempty"`   // 查询失败原因
+}
+
+// checkDANE 查询 _端口._tcp.主机名 的TLSA记录，逐条与服务器证书链比对
+func (a *App) checkDANE(ctx context.Context, info *CertificateInfo, host string, port int, certs []*x509.Certificate, roots *x509.CertPool) {
+	result := &DANEInfo{Name: fmt.Sprintf("_%d._tcp.%s", port, strings.TrimSuffix(host, "."))}
+	info.DANE = result
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rdata, err := a.recordResolver().LookupRecords(ctx, result.Name, dnsTypeTLSA)
+	if err != nil {
+		result.Error = fmt.Sprintf("查询TLSA记录失败: %v", err)
+		return
+	}
+	if len(rdata) == 0 {
+		return
+	}
+
+	// PKIX-TA/PKIX-EE 需要证书链通过常规校验，DANE-TA 需要能构建到匹配证书的链
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	pkixChains, pkixErr := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       host,
+	})
+
+	for _, data := range rdata {
+		record := evaluateTLSA(data, certs, host, intermediates, pkixChains, pkixErr)
+		if record.Pass {
+			result.Valid = true
+		}
+		result.Records = append(result.Records, record)
+	}
+
+	if !result.Valid {
+		info.Warnings = append(info.Warnings,
+			fmt.Sprintf("%s 的 %d 条TLSA记录均与证书链不匹配，DANE校验失败", result.Name, len(result.Records)))
+	}
+}
+
+// evaluateTLSA 解析单条TLSA记录的RDATA（usage(1) + selector(1) + matching type(1) + 数据）并校验
+func evaluateTLSA(data []byte, certs []*x509.Certificate, host string, intermediates *x509.CertPool,
+	pkixChains [][]*x509.Certificate, pkixErr error) TLSARecordResult {
+	if len(data) < 4 {
+		return TLSARecordResult{Reason: "记录长度无效"}
+	}
+
+	record := TLSARecordResult{
+		Usage:        int(data[0]),
+		Selector:     int(data[1]),
+		MatchingType: int(data[2]),
+		Data:         hex.EncodeToString(data[3:]),
+	}
+	record.Description = tlsaDescription(record.Usage, record.Selector, record.MatchingType)
+	association := data[3:]
+
+	if record.Usage > tlsaUsageDANEEE {
+		record.Reason = fmt.Sprintf("不支持的证书用途 %d", record.Usage)
+		return record
+	}
+	if record.Selector >= len(tlsaSelectorNames) {
+		record.Reason = fmt.Sprintf("不支持的选择器 %d", record.Selector)
+		return record
+	}
+	if record.MatchingType >= len(tlsaMatchingNames) {
+		record.Reason = fmt.Sprintf("不支持的匹配类型 %d", record.MatchingType)
+		return record
+	}
+
+	matches := func(cert *x509.Certificate) bool {
+		return tlsaMatches(cert, record.Selector, record.MatchingType, association)
+	}
+
+	switch record.Usage {
+	case tlsaUsageDANEEE:
+		if matches(certs[0]) {
+			record.Pass, record.Matched = true, displayName(certs[0])
+		} else {
+			record.Reason = "与服务器证书不匹配"
+		}
+
+	case tlsaUsagePKIXEE:
+		if !matches(certs[0]) {
+			record.Reason = "与服务器证书不匹配"
+		} else if pkixErr != nil {
+			record.Reason = fmt.Sprintf("服务器证书匹配，但PKIX校验失败: %s", describeVerifyError(pkixErr))
+		} else {
+			record.Pass, record.Matched = true, displayName(certs[0])
+		}
+
+	case tlsaUsageDANETA:
+		// 信任锚须由服务器在链中发送，且叶子证书能构建到该证书
+		for _, anchor := range certs[1:] {
+			if !matches(anchor) {
+				continue
+			}
+			record.Matched = displayName(anchor)
+			anchors := x509.NewCertPool()
+			anchors.AddCert(anchor)
+			_, err := certs[0].Verify(x509.VerifyOptions{
+				Roots:         anchors,
+				Intermediates: intermediates,
+				DNSName:       host,
+			})
+			if err == nil {
+				record.Pass, record.Reason = true, ""
+				break
+			}
+			record.Reason = fmt.Sprintf("信任锚 %s 匹配，但无法构建有效的证书链: %s", record.Matched, describeVerifyError(err))
+		}
+		if record.Matched == "" {
+			record.Reason = "与链中的CA证书均不匹配"
+		}
+
+	case tlsaUsagePKIXTA:
+		if pkixErr != nil {
+			record.Reason = fmt.Sprintf("PKIX校验失败: %s", describeVerifyError(pkixErr))
+			break
+		}
+		for _, chain := range pkixChains {
+			for _, cert := range chain[1:] {
+				if matches(cert) {
+					record.Pass, record.Matched = true, displayName(cert)
+					break
+				}
+			}
+			if record.Pass {
+				break
+			}
+		}
+		if !record.Pass {
+			record.Reason = "与校验通过的证书链中的CA证书均不匹配"
+		}
+	}
+
+	return record
+}
+
+// tlsaMatches 按选择器和匹配类型比较证书与TLSA关联数据
+func tlsaMatches(cert *x509.Certificate, selector, matchingType int, association []byte) bool {
+	content := cert.Raw
+	if selector == 1 {
+		content = cert.RawSubjectPublicKeyInfo
+	}
+
+	switch matchingType {
+	case 1:
+		sum := sha256.Sum256(content)
+		content = sum[:]
+	case 2:
+		sum := sha512.Sum512(content)
+		content = sum[:]
+	}
+	return bytes.Equal(content, association)
+}
+
+// tlsaDescription 返回TLSA记录参数的助记形式
+func tlsaDescription(usage, selector, matchingType int) string {
+	name := func(names []string, value int) string {
+		if value < len(names) {
+			return names[value]
+		}
+		return fmt.Sprintf("%d", value)
+	}
+	return fmt.Sprintf("%s %s %s", name(tlsaUsageNames, usage), name(tlsaSelectorNames, selector), name(tlsaMatchingNames, matchingType))
+}
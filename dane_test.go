@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateTLSA(t *testing.T) {
+	root := newTestCert(t, "Test Root", nil)
+	intermediate := newTestCert(t, "Test Intermediate", root)
+	leaf := newTestCert(t, "example.com", intermediate, "example.com")
+	other := newTestCert(t, "Other CA", nil)
+
+	certs := []*x509.Certificate{leaf.cert, intermediate.cert, root.cert}
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate.cert)
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	pkixChains, pkixErr := leaf.cert.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, DNSName: "example.com"})
+	if pkixErr != nil {
+		t.Fatal(pkixErr)
+	}
+	_, untrustedErr := leaf.cert.Verify(x509.VerifyOptions{Roots: x509.NewCertPool(), Intermediates: intermediates, DNSName: "example.com"})
+
+	spki256 := func(cert *x509.Certificate) []byte {
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		return sum[:]
+	}
+	cert512 := func(cert *x509.Certificate) []byte {
+		sum := sha512.Sum512(cert.Raw)
+		return sum[:]
+	}
+	record := func(usage, selector, matchingType byte, association []byte) []byte {
+		return append([]byte{usage, selector, matchingType}, association...)
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		pkixErr     error
+		pass        bool
+		matched     string
+		reason      string
+		description string
+	}{
+		{"DANE-EE SPKI SHA-256", record(3, 1, 1, spki256(leaf.cert)), nil, true, "example.com", "", "DANE-EE SPKI SHA2-256"},
+		{"DANE-EE 完整证书", record(3, 0, 0, leaf.cert.Raw), nil, true, "example.com", "", "DANE-EE Cert Full"},
+		{"DANE-EE 不要求PKIX", record(3, 1, 1, spki256(leaf.cert)), untrustedErr, true, "example.com", "", ""},
+		{"DANE-EE 不匹配", record(3, 1, 1, spki256(other.cert)), nil, false, "", "与服务器证书不匹配", ""},
+		{"PKIX-EE 匹配", record(1, 0, 2, cert512(leaf.cert)), nil, true, "example.com", "", "PKIX-EE Cert SHA2-512"},
+		{"PKIX-EE 校验失败", record(1, 0, 2, cert512(leaf.cert)), untrustedErr, false, "", "PKIX校验失败", ""},
+		{"DANE-TA 中间证书", record(2, 1, 1, spki256(intermediate.cert)), untrustedErr, true, "Test Intermediate", "", ""},
+		{"DANE-TA 不在链中", record(2, 1, 1, spki256(other.cert)), nil, false, "", "均不匹配", ""},
+		{"DANE-TA 不匹配服务器证书", record(2, 1, 1, spki256(leaf.cert)), nil, false, "", "均不匹配", ""},
+		{"PKIX-TA 根证书", record(0, 1, 1, spki256(root.cert)), nil, true, "Test Root", "", ""},
+		{"PKIX-TA 校验失败", record(0, 1, 1, spki256(root.cert)), untrustedErr, false, "", "PKIX校验失败", ""},
+		{"PKIX-TA 不匹配", record(0, 1, 1, spki256(other.cert)), nil, false, "", "均不匹配", ""},
+		{"记录过短", []byte{3, 1, 1}, nil, false, "", "记录长度无效", ""},
+		{"未知用途", record(4, 1, 1, spki256(leaf.cert)), nil, false, "", "不支持的证书用途 4", "4 SPKI SHA2-256"},
+		{"未知选择器", record(3, 2, 1, spki256(leaf.cert)), nil, false, "", "不支持的选择器 2", ""},
+		{"未知匹配类型", record(3, 1, 3, spki256(leaf.cert)), nil, false, "", "不支持的匹配类型 3", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chains := pkixChains
+			if tt.pkixErr != nil {
+				chains = nil
+			}
+			got := evaluateTLSA(tt.data, certs, "example.com", intermediates, chains, tt.pkixErr)
+			if got.Pass != tt.pass || got.Matched != tt.matched || !strings.Contains(got.Reason, tt.reason) {
+				t.Fatalf("evaluateTLSA = 通过 %v，匹配 %q，原因 %q；期望 %v, %q, %q", got.Pass, got.Matched, got.Reason, tt.pass, tt.matched, tt.reason)
+			}
+			if tt.pass && got.Reason != "" {
+				t.Errorf("通过的记录不应有原因: %q", got.Reason)
+			}
+			if tt.description != "" && got.Description != tt.description {
+				t.Errorf("助记形式为 %q，期望 %q", got.Description, tt.description)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNS记录类型（dnsmessage未内置的类型）
+const (
+	dnsTypeTLSA = 52
+	dnsTypeCAA  = 257
+)
+
+// RecordResolver DNS记录查询接口（CAA、TLSA等），返回应答中指定类型记录的RDATA，
+// 域名不存在或没有该类型记录时返回空列表。可替换为本地DNS以便测试
+type RecordResolver interface {
+	LookupRecords(ctx context.Context, name string, qtype uint16) ([][]byte, error)
+}
+
+// dnsClient 直接向DNS服务器发送查询的 RecordResolver 实现（UDP，应答被截断时改用TCP）
+type dnsClient struct {
+	servers []string // DNS服务器地址（IP:端口），依次尝试
+	err     error    // 读取系统DNS配置失败的原因
+}
+
+// dnsUnreachableError 无法连接DNS服务器或服务器没有应答
+type dnsUnreachableError struct {
+	err error
+}
+
+func (e *dnsUnreachableError) Error() string {
+	return e.err.Error()
+}
+
+// recordResolver 返回当前使用的DNS记录解析器：设置中指定了DNS服务器时使用该服务器，否则使用系统DNS配置
+func (a *App) recordResolver() RecordResolver {
+	if a.dnsResolver != nil {
+		return a.dnsResolver
+	}
+	if server := a.currentSettings().DNSServer; server != "" {
+		return &dnsClient{servers: []string{server}}
+	}
+	servers, err := systemDNSServers()
+	return &dnsClient{servers: servers, err: err}
+}
+
+// normalizeDNSServer 规范化DNS服务器地址（IP或IP:端口，默认端口53），为空表示使用系统DNS配置
+func normalizeDNSServer(server string) (string, error) {
+	server = strings.TrimSpace(server)
+	if server == "" {
+		return "", nil
+	}
+	if ip := net.ParseIP(strings.Trim(server, "[]")); ip != nil {
+		return net.JoinHostPort(ip.String(), "53"), nil
+	}
+	host, port, err := net.SplitHostPort(server)
+	if err == nil && net.ParseIP(host) != nil {
+		if n, err := strconv.Atoi(port); err == nil && n > 0 && n <= 65535 {
+			return net.JoinHostPort(host, port), nil
+		}
+	}
+	return "", fmt.Errorf("无效的DNS服务器地址: %s（应为IP或IP:端口）", server)
+}
+
+// LookupRecords 依次向各DNS服务器查询，返回第一个成功的应答；所有服务器都无法连接时明确报告DNS不可达
+func (c *dnsClient) LookupRecords(ctx context.Context, name string, qtype uint16) ([][]byte, error) {
+	if len(c.servers) == 0 {
+		if c.err != nil {
+			return nil, fmt.Errorf("读取系统DNS配置失败（%v），请在设置中指定DNS服务器", c.err)
+		}
+		return nil, fmt.Errorf("未找到系统DNS服务器，请在设置中指定DNS服务器")
+	}
+
+	var lastErr error
+	unreachable := true
+	for _, server := range c.servers {
+		records, err := c.exchange(ctx, server, name, qtype)
+		if err == nil {
+			return records, nil
+		}
+		lastErr = err
+		var netErr *dnsUnreachableError
+		if !errors.As(err, &netErr) {
+			unreachable = false
+		}
+	}
+	if unreachable && ctx.Err() == nil {
+		return nil, fmt.Errorf("DNS不可达（%s）: %v", strings.Join(c.servers, "、"), lastErr)
+	}
+	return nil, lastErr
+}
+
+// exchange 向单个DNS服务器查询
+func (c *dnsClient) exchange(ctx context.Context, server, name string, qtype uint16) ([][]byte, error) {
+	qname, err := dnsmessage.NewName(fqdn(name))
+	if err != nil {
+		return nil, fmt.Errorf("无效的域名 %s: %v", name, err)
+	}
+
+	id := uint16(rand.Intn(1 << 16))
+	query, err := buildDNSQuery(id, qname, dnsmessage.Type(qtype))
+	if err != nil {
+		return nil, fmt.Errorf("构造DNS查询失败: %v", err)
+	}
+
+	resp, err := dnsRoundTrip(ctx, "udp", server, query)
+	if err != nil {
+		return nil, &dnsUnreachableError{err}
+	}
+	var msg dnsmessage.Message
+	if err := msg.Unpack(resp); err != nil {
+		return nil, fmt.Errorf("解析DNS应答失败: %v", err)
+	}
+	if msg.Truncated {
+		if resp, err = dnsRoundTrip(ctx, "tcp", server, query); err != nil {
+			return nil, &dnsUnreachableError{err}
+		}
+		if err := msg.Unpack(resp); err != nil {
+			return nil, fmt.Errorf("解析DNS应答失败: %v", err)
+		}
+	}
+	if msg.ID != id {
+		return nil, fmt.Errorf("DNS应答ID不匹配")
+	}
+
+	switch msg.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("DNS查询 %s 失败: %v", name, msg.RCode)
+	}
+
+	// 应答中可能包含CNAME，只取所查询类型的记录
+	var records [][]byte
+	for _, answer := range msg.Answers {
+		if answer.Header.Type != dnsmessage.Type(qtype) {
+			continue
+		}
+		if body, ok := answer.Body.(*dnsmessage.UnknownResource); ok {
+			records = append(records, body.Data)
+		}
+	}
+	return records, nil
+}
+
+// buildDNSQuery 构造请求递归查询的DNS报文（带EDNS0以接收较大的UDP应答）
+func buildDNSQuery(id uint16, name dnsmessage.Name, qtype dnsmessage.Type) ([]byte, error) {
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{ID: id, RecursionDesired: true})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(4096, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, err
+	}
+	if err := b.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// dnsRoundTrip 发送DNS报文并读取应答，TCP时加两字节长度前缀
+func dnsRoundTrip(ctx context.Context, network, server string, query []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, fmt.Errorf("连接DNS服务器 %s 失败: %v", server, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if network == "tcp" {
+		packet := make([]byte, 2+len(query))
+		binary.BigEndian.PutUint16(packet, uint16(len(query)))
+		copy(packet[2:], query)
+		if _, err := conn.Write(packet); err != nil {
+			return nil, fmt.Errorf("发送DNS查询失败: %v", err)
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, fmt.Errorf("读取DNS应答失败: %v", err)
+		}
+		resp := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			return nil, fmt.Errorf("读取DNS应答失败: %v", err)
+		}
+		return resp, nil
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, fmt.Errorf("发送DNS查询失败: %v", err)
+	}
+	resp := make([]byte, 65535)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return nil, fmt.Errorf("读取DNS应答失败: %v", err)
+	}
+	return resp[:n], nil
+}
+
+// fqdn 为域名补全末尾的点
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
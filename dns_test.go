@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestNormalizeDNSServer(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"  ", "", false},
+		{"10.0.0.53", "10.0.0.53:53", false},
+		{"10.0.0.53:5353", "10.0.0.53:5353", false},
+		{"2001:db8::53", "[2001:db8::53]:53", false},
+		{"[2001:db8::53]", "[2001:db8::53]:53", false},
+		{"[2001:db8::53]:5353", "[2001:db8::53]:5353", false},
+		{"dns.example.com", "", true},
+		{"10.0.0.53:0", "", true},
+		{"10.0.0.53:70000", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeDNSServer(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeDNSServer(%q) = %q, %v，期望 %q（出错 %v）", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// serveDNS 在本地UDP端口上应答一次查询，answers 为应答中的记录（类型与查询相同）
+func serveDNS(t *testing.T, rcode dnsmessage.RCode, answers ...[]byte) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		var query dnsmessage.Message
+		if err := query.Unpack(buf[:n]); err != nil {
+			return
+		}
+		q := query.Questions[0]
+		resp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: query.ID, Response: true, RCode: rcode},
+			Questions: query.Questions,
+		}
+		for _, data := range answers {
+			resp.Answers = append(resp.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: 60},
+				Body:   &dnsmessage.UnknownResource{Type: q.Type, Data: data},
+			})
+		}
+		packed, err := resp.Pack()
+		if err == nil {
+			conn.WriteTo(packed, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestDNSClientLookupRecords(t *testing.T) {
+	caa := []byte{0, 5, 'i', 's', 's', 'u', 'e', 'l', 'e', 't', 's', 'e', 'n', 'c', 'r', 'y', 'p', 't', '.', 'o', 'r', 'g'}
+	client := &dnsClient{servers: []string{serveDNS(t, dnsmessage.RCodeSuccess, caa)}}
+	records, err := client.LookupRecords(context.Background(), "example.com", dnsTypeCAA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || string(records[0]) != string(caa) {
+		t.Fatalf("应答记录不正确: %q", records)
+	}
+
+	client = &dnsClient{servers: []string{serveDNS(t, dnsmessage.RCodeNameError)}}
+	records, err = client.LookupRecords(context.Background(), "missing.example.com", dnsTypeCAA)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("域名不存在时应返回空列表，得到 %q, %v", records, err)
+	}
+
+	client = &dnsClient{servers: []string{serveDNS(t, dnsmessage.RCodeServerFailure)}}
+	if _, err := client.LookupRecords(context.Background(), "example.com", dnsTypeCAA); err == nil || strings.Contains(err.Error(), "DNS不可达") {
+		t.Fatalf("SERVFAIL应报告查询失败而不是不可达，得到 %v", err)
+	}
+}
+
+func TestDNSClientUnreachable(t *testing.T) {
+	// 监听后立即关闭，发往该端口的查询会被拒绝
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := conn.LocalAddr().String()
+	conn.Close()
+
+	client := &dnsClient{servers: []string{server}}
+	_, err = client.LookupRecords(context.Background(), "example.com", dnsTypeTLSA)
+	if err == nil || !strings.Contains(err.Error(), "DNS不可达") {
+		t.Fatalf("期望报告DNS不可达，得到 %v", err)
+	}
+
+	client = &dnsClient{}
+	if _, err := client.LookupRecords(context.Background(), "example.com", dnsTypeTLSA); err == nil || !strings.Contains(err.Error(), "请在设置中指定DNS服务器") {
+		t.Fatalf("没有DNS服务器时应提示在设置中指定，得到 %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build windows
+
+package main
+
+import (
+	"net"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// systemDNSServers 读取已启用网卡的DNS服务器（与系统解析器使用的配置相同）
+func systemDNSServers() ([]string, error) {
+	size := uint32(15000)
+	var buf []byte
+	for {
+		buf = make([]byte, size)
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC,
+			windows.GAA_FLAG_SKIP_UNICAST|windows.GAA_FLAG_SKIP_ANYCAST|windows.GAA_FLAG_SKIP_MULTICAST,
+			0, (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])), &size)
+		if err == nil {
+			break
+		}
+		if err != windows.ERROR_BUFFER_OVERFLOW {
+			return nil, err
+		}
+	}
+
+	var servers []string
+	seen := make(map[string]bool)
+	for aa := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0])); aa != nil; aa = aa.Next {
+		if aa.OperStatus != windows.IfOperStatusUp || aa.IfType == windows.IF_TYPE_SOFTWARE_LOOPBACK {
+			continue
+		}
+		for dns := aa.FirstDnsServerAddress; dns != nil; dns = dns.Next {
+			ip := dns.Address.IP()
+			// 未配置IPv6 DNS时系统填入的 fec0:0:0:ffff::1-3（已废弃的站点本地地址）
+			if ip == nil || (len(ip) == net.IPv6len && ip.To4() == nil && ip[0] == 0xfe && ip[1] == 0xc0) {
+				continue
+			}
+			server := net.JoinHostPort(ip.String(), "53")
+			if !seen[server] {
+				seen[server] = true
+				servers = append(servers, server)
+			}
+		}
+	}
+	return servers, nil
+}
@@ -3,7 +3,7 @@ import './app.css';
 import './features.css'; // 引入新功能样式
 import './features.js'; // 引入新功能模块
 
-import {CheckCertificate, BatchCheckCertificates, GetHistory, ClearHistory, AddWatchedDomain, GetWatchedDomains, RemoveWatchedDomain, UpdateWatchedDomainNickname, RefreshWatchedDomain, UpdateNotifySettings, UpdateManualCertInfo, DisableManualMode, CheckNotifications, RefreshAllWatchedDomains, ImportDomainsFromText} from '../wailsjs/go/main/App';
+import {CheckCertificateWithOptions, BatchCheckCertificates, ClearHistory, AddWatchedDomain, GetWatchedDomains, RemoveWatchedDomain, UpdateWatchedDomainNickname, RefreshWatchedDomain, UpdateNotifySettings, UpdateManualCertInfo, DisableManualMode, CheckNotifications, RefreshAllWatchedDomains, ImportDomainsFromText, CancelProbes, CheckCoverage, GetSharedCertificateReport, GetSchemaStatus, GetHistoryRecord, QueryHistory, GetDomainTimeline, AcknowledgeCertEvents} from '../wailsjs/go/main/App';
 
 // 渲染HTML结构
 document.querySelector('#app').innerHTML = `
@@ -82,6 +82,39 @@ document.querySelector('#app').innerHTML = `
                         <span id="btnText">查询</span>
                     </button>
                 </div>
+                <div class="probe-options">
+                    <label class="probe-option">
+                        <input type="checkbox" id="optPerIp" />
+                        <span>逐IP探测（检查所有A/AAAA记录）</span>
+                    </label>
+                    <input type="text" id="optConnectAddress" class="probe-option-input" placeholder="连接地址，如 10.0.0.5" autocomplete="off" />
+                    <input type="text" id="optSni" class="probe-option-input" placeholder="SNI，默认为域名" autocomplete="off" />
+                    <input type="text" id="optProxy" class="probe-option-input" placeholder="代理，如 socks5://127.0.0.1:1080" autocomplete="off" />
+                    <label class="probe-option">
+                        <input type="checkbox" id="optNoSni" />
+                        <span>不发送SNI</span>
+                    </label>
+                    <label class="probe-option">
+                        <input type="checkbox" id="optDeepScan" />
+                        <span>深度扫描（协议版本与加密套件）</span>
+                    </label>
+                    <label class="probe-option">
+                        <input type="checkbox" id="optCheckCaa" />
+                        <span>检查CAA记录</span>
+                    </label>
+                    <label class="probe-option">
+                        <input type="checkbox" id="optCheckDane" />
+                        <span>检查DANE/TLSA记录</span>
+                    </label>
+                    <label class="probe-option">
+                        <input type="checkbox" id="optCheckHttp" />
+                        <span>HTTP检查（HSTS与重定向）</span>
+                    </label>
+                    <label class="probe-option">
+                        <input type="checkbox" id="optWarnHsts" />
+                        <span>未启用HSTS时警告</span>
+                    </label>
+                </div>
             </div>
         </div>
 
@@ -116,6 +149,38 @@ document.querySelector('#app').innerHTML = `
                         </button>
                     </div>
                 </div>
+                <div class="probe-options">
+                    <input type="text" id="historyDomain" class="probe-option-input" placeholder="域名包含..." onchange="loadHistory()" />
+                    <select id="historyStatus" class="probe-option-input" onchange="loadHistory()">
+                        <option value="">全部状态</option>
+                        <option value="safe">安全</option>
+                        <option value="warning,danger">即将过期</option>
+                        <option value="expired">已过期</option>
+                        <option value="revoked">已吊销</option>
+                        <option value="untrusted,mismatch">校验失败</option>
+                    </select>
+                    <input type="text" id="historyIssuer" class="probe-option-input" placeholder="颁发者包含..." onchange="loadHistory()" />
+                    <input type="date" id="historyFrom" class="probe-option-input" title="查询时间起" onchange="loadHistory()" />
+                    <input type="date" id="historyTo" class="probe-option-input" title="查询时间止" onchange="loadHistory()" />
+                    <select id="historyKeyAlgorithm" class="probe-option-input" onchange="loadHistory()">
+                        <option value="">全部公钥算法</option>
+                        <option value="RSA">RSA</option>
+                        <option value="ECDSA">ECDSA</option>
+                        <option value="Ed25519">Ed25519</option>
+                    </select>
+                    <select id="historySignatureAlgorithm" class="probe-option-input" onchange="loadHistory()">
+                        <option value="">全部签名算法</option>
+                        <option value="SHA256-RSA">SHA256-RSA</option>
+                        <option value="SHA384-RSA">SHA384-RSA</option>
+                        <option value="ECDSA-SHA256">ECDSA-SHA256</option>
+                        <option value="ECDSA-SHA384">ECDSA-SHA384</option>
+                        <option value="SHA1-RSA">SHA1-RSA</option>
+                    </select>
+                    <label class="probe-option">
+                        <input type="checkbox" id="historyWeakOnly" onchange="loadHistory()" />
+                        <span>仅显示弱配置</span>
+                    </label>
+                </div>
                 <div id="historyContent" class="history-content">
                     <p class="empty-hint">正在加载...</p>
                 </div>
@@ -137,6 +202,9 @@ document.querySelector('#app').innerHTML = `
                         <button class="btn-secondary" onclick="showBatchImportDialog()">
                             <span>📥</span> 批量导入
                         </button>
+                        <button class="btn-secondary" onclick="showSharedCertReport()">
+                            <span>🧩</span> 共用证书
+                        </button>
                         <button class="btn-primary" onclick="showAddWatchDialog()">
                             <span>➕</span> 添加关注
                         </button>
@@ -247,6 +315,7 @@ document.querySelector('#app').innerHTML = `
         <div class="loading" id="loading" style="display: none;">
             <div class="spinner"></div>
             <p id="loadingText">正在查询证书信息...</p>
+            <button class="btn-secondary" onclick="cancelQuery()">取消</button>
         </div>
             </div>
         </main>
@@ -347,7 +416,7 @@ window.checkCert = async function() {
     resultCard.style.display = 'none';
 
     try {
-        const result = await CheckCertificate(domain);
+        const result = await CheckCertificateWithOptions(domain, readProbeOptions());
         
         if (result.success) {
             showSuccess(result.data);
@@ -362,6 +431,36 @@ window.checkCert = async function() {
     }
 };
 
+// 读取单个查询的探测选项
+function readProbeOptions() {
+    return {
+        perIp: document.getElementById('optPerIp').checked,
+        connectAddress: document.getElementById('optConnectAddress').value.trim(),
+        sni: document.getElementById('optSni').value.trim(),
+        proxy: document.getElementById('optProxy').value.trim(),
+        noSni: document.getElementById('optNoSni').checked,
+        deepScan: document.getElementById('optDeepScan').checked,
+        checkCaa: document.getElementById('optCheckCaa').checked,
+        checkDane: document.getElementById('optCheckDane').checked,
+        checkHttp: document.getElementById('optCheckHttp').checked,
+        warnMissingHsts: document.getElementById('optWarnHsts').checked
+    };
+}
+
+// 格式化连接方式，仅在指定了连接地址或SNI与域名不同时返回说明
+function formatProbeRoute(data) {
+    const parts = [];
+    if (data.connectAddress) {
+        parts.push(`连接 ${data.connectAddress}`);
+    }
+    if (!data.sni) {
+        parts.push('未发送SNI');
+    } else if (data.sni !== data.domain) {
+        parts.push(`SNI ${data.sni}`);
+    }
+    return parts.join(' · ');
+}
+
 // 设置加载状态
 function setLoading(isLoading, text = '正在查询证书信息...') {
     loading.style.display = isLoading ? 'flex' : 'none';
@@ -380,6 +479,24 @@ function setLoading(isLoading, text = '正在查询证书信息...') {
     }
 }
 
+// 取消进行中的查询
+window.cancelQuery = function() {
+    CancelProbes();
+    loadingText.textContent = '正在取消...';
+};
+
+// 格式化查询目标，非默认端口时附加端口号，STARTTLS协议时附加协议前缀
+function formatTarget(domain, port, protocol) {
+    const host = domain.includes(':') ? `[${domain}]` : domain;
+    if (protocol) {
+        return `${protocol}://${host}:${port}`;
+    }
+    if (!port || port === 443) {
+        return domain;
+    }
+    return `${host}:${port}`;
+}
+
 // 显示成功结果
 function showSuccess(data) {
     const statusClass = `status-${data.status}`;
@@ -387,14 +504,20 @@ function showSuccess(data) {
         'safe': '安全',
         'warning': '即将过期',
         'danger': '即将过期',
-        'expired': '已过期'
+        'expired': '已过期',
+        'revoked': '已吊销',
+        'untrusted': '不受信任',
+        'mismatch': '域名不匹配'
     };
 
     const statusIcon = {
         'safe': '✅',
         'warning': '⚠️',
         'danger': '⚠️',
-        'expired': '❌'
+        'expired': '❌',
+        'revoked': '⛔',
+        'untrusted': '🚫',
+        'mismatch': '🚫'
     };
 
     resultContent.innerHTML = `
@@ -407,9 +530,30 @@ function showSuccess(data) {
             <div class="info-grid">
                 <div class="info-item">
                     <div class="info-label">域名</div>
-                    <div class="info-value domain-value">${data.domain}</div>
+                    <div class="info-value domain-value">${formatTarget(data.domain, data.port, data.protocol)}</div>
                 </div>
                 
+                ${formatProbeRoute(data) ? `
+                <div class="info-item">
+                    <div class="info-label">连接方式</div>
+                    <div class="info-value">${formatProbeRoute(data)}</div>
+                </div>
+                ` : ''}
+                
+                ${data.tlsVersion ? `
+                <div class="info-item">
+                    <div class="info-label">连接信息</div>
+                    <div class="info-value">${data.tlsVersion}${data.connectedIp ? ` · ${data.connectedIp}` : ''}${data.durationMs ? ` · ${data.durationMs} ms` : ''}</div>
+                </div>
+                ` : ''}
+                
+                ${data.queryTime ? `
+                <div class="info-item">
+                    <div class="info-label">查询时间</div>
+                    <div class="info-value">${data.queryTime}</div>
+                </div>
+                ` : ''}
+                
                 <div class="info-item highlight">
                     <div class="info-label">剩余天数</div>
                     <div class="info-value days-value ${statusClass}">
@@ -446,19 +590,357 @@ function showSuccess(data) {
                     <div class="info-label">版本</div>
                     <div class="info-value">v${data.version}</div>
                 </div>
+                
+                <div class="info-item">
+                    <div class="info-label">公钥</div>
+                    <div class="info-value">${data.publicKeyAlgorithm} ${data.publicKeySize}</div>
+                </div>
+                
+                <div class="info-item">
+                    <div class="info-label">签名算法</div>
+                    <div class="info-value">${data.signatureAlgorithm}</div>
+                </div>
+                
+                <div class="info-item">
+                    <div class="info-label">密钥用法</div>
+                    <div class="info-value">${(data.keyUsage || []).join(', ') || 'N/A'}</div>
+                </div>
+                
+                <div class="info-item">
+                    <div class="info-label">扩展密钥用法</div>
+                    <div class="info-value">${(data.extKeyUsage || []).join(', ') || 'N/A'}</div>
+                </div>
+                
+                <div class="info-item">
+                    <div class="info-label">基本约束</div>
+                    <div class="info-value">${data.basicConstraints || 'N/A'}</div>
+                </div>
+                
+                <div class="info-item">
+                    <div class="info-label">SHA-1指纹</div>
+                    <div class="info-value serial-value">${data.fingerprintSha1}</div>
+                </div>
+                
+                <div class="info-item">
+                    <div class="info-label">SHA-256指纹</div>
+                    <div class="info-value serial-value">${data.fingerprintSha256}</div>
+                </div>
             </div>
             
-            ${data.sanDomains && data.sanDomains.length > 0 ? `
+            ${data.weakFlags && data.weakFlags.length > 0 ? `
+                <div class="verify-section">
+                    <div class="san-header">
+                        <span class="san-icon">⚠️</span>
+                        <span class="san-title">弱配置</span>
+                    </div>
+                    <ul class="verify-errors">
+                        ${data.weakFlags.map(f => `<li>${f}</li>`).join('')}
+                    </ul>
+                </div>
+            ` : ''}
+            
+            ${data.warnings && data.warnings.length > 0 ? `
+                <div class="verify-section">
+                    <div class="san-header">
+                        <span class="san-icon">⚠️</span>
+                        <span class="san-title">警告</span>
+                    </div>
+                    <ul class="verify-errors">
+                        ${data.warnings.map(w => `<li>${w}</li>`).join('')}
+                    </ul>
+                </div>
+            ` : ''}
+            
+            ${data.ipResults && data.ipResults.length > 0 ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">🖧</span>
+                        <span class="san-title">逐IP探测结果${data.ipInconsistent ? '（各节点证书不一致）' : ''}</span>
+                        <span class="san-count">共 ${data.ipResults.length} 个</span>
+                    </div>
+                    <div class="chain-list">
+                        ${data.ipResults.map(r => `
+                            <div class="chain-item">
+                                <div class="chain-item-title">${r.ip} ${r.success ? (statusIcon[r.status] || '') : '❌'}</div>
+                                ${r.success ? `
+                                    <div class="chain-item-detail">序列号：${r.serialNumber}</div>
+                                    <div class="chain-item-detail">过期时间：${r.notAfter}（剩余 ${r.daysRemaining} 天）</div>
+                                ` : `<div class="chain-item-detail">${r.error}</div>`}
+                            </div>
+                        `).join('')}
+                    </div>
+                </div>
+            ` : ''}
+            
+            ${data.verifyErrors && data.verifyErrors.length > 0 ? `
+                <div class="verify-section">
+                    <div class="san-header">
+                        <span class="san-icon">🚫</span>
+                        <span class="san-title">证书校验未通过</span>
+                    </div>
+                    <ul class="verify-errors">
+                        ${data.verifyErrors.map(err => `<li>${err}</li>`).join('')}
+                    </ul>
+                </div>
+            ` : ''}
+            
+            ${data.ocsp ? `
+                <div class="verify-section">
+                    <div class="san-header">
+                        <span class="san-icon">📜</span>
+                        <span class="san-title">吊销状态（OCSP）</span>
+                    </div>
+                    <div class="info-grid">
+                        <div class="info-item">
+                            <div class="info-label">状态</div>
+                            <div class="info-value">${{ 'good': '✅ 正常', 'revoked': '⛔ 已吊销', 'unknown': '❔ 未知' }[data.ocsp.status] || data.ocsp.status}</div>
+                        </div>
+                        <div class="info-item">
+                            <div class="info-label">OCSP装订</div>
+                            <div class="info-value">${data.ocsp.stapled ? '已装订' : '未装订'}</div>
+                        </div>
+                        ${data.ocsp.revokedAt ? `
+                        <div class="info-item">
+                            <div class="info-label">吊销时间</div>
+                            <div class="info-value">${data.ocsp.revokedAt}（${data.ocsp.revocationReason}）</div>
+                        </div>
+                        ` : ''}
+                        ${data.ocsp.nextUpdate ? `
+                        <div class="info-item">
+                            <div class="info-label">响应有效期至</div>
+                            <div class="info-value">${data.ocsp.nextUpdate}</div>
+                        </div>
+                        ` : ''}
+                    </div>
+                    ${data.ocsp.error ? `
+                        <ul class="verify-errors">
+                            <li>${data.ocsp.error}</li>
+                        </ul>
+                    ` : ''}
+                </div>
+            ` : ''}
+            
+            ${data.crl && data.crl.length > 0 ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">📋</span>
+                        <span class="san-title">吊销状态（CRL）</span>
+                        <span class="san-count">共 ${data.crl.length} 个</span>
+                    </div>
+                    <div class="chain-list">
+                        ${data.crl.map(c => `
+                            <div class="chain-item">
+                                <div class="chain-item-title">${c.certificate} ${c.error ? '❔' : (c.revoked ? '⛔ 已吊销' : '✅ 未吊销')}</div>
+                                ${c.revoked ? `<div class="chain-item-detail">吊销时间：${c.revokedAt}（${c.reason}）</div>` : ''}
+                                ${c.error ? `<div class="chain-item-detail">${c.error}</div>` : ''}
+                                <div class="chain-item-detail">分发点：${c.url}</div>
+                                ${c.nextUpdate ? `<div class="chain-item-detail">下次更新：${c.nextUpdate}${c.stale ? '（CRL已过期）' : ''}${c.cached ? ' · 缓存' : ''}</div>` : ''}
+                            </div>
+                        `).join('')}
+                    </div>
+                </div>
+            ` : ''}
+            
+            ${data.tlsScan ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">🛡️</span>
+                        <span class="san-title">TLS配置${data.tlsScan.grade ? `（评级 ${data.tlsScan.grade}）` : ''}</span>
+                    </div>
+                    ${data.tlsScan.error ? `
+                        <ul class="verify-errors"><li>${data.tlsScan.error}</li></ul>
+                    ` : `
+                        <div class="info-grid">
+                            <div class="info-item">
+                                <div class="info-label">支持的协议</div>
+                                <div class="info-value">${(data.tlsScan.supportedVersions || []).join('、')}</div>
+                            </div>
+                            <div class="info-item">
+                                <div class="info-label">协商结果</div>
+                                <div class="info-value">${data.tlsScan.negotiatedVersion} · ${data.tlsScan.negotiatedCipher}</div>
+                            </div>
+                            <div class="info-item">
+                                <div class="info-label">ALPN</div>
+                                <div class="info-value">${data.tlsScan.alpn || '无'}</div>
+                            </div>
+                            <div class="info-item">
+                                <div class="info-label">支持的曲线</div>
+                                <div class="info-value">${(data.tlsScan.supportedCurves || []).join('、') || '无'}</div>
+                            </div>
+                        </div>
+                        ${data.tlsScan.issues && data.tlsScan.issues.length > 0 ? `
+                            <ul class="verify-errors">
+                                ${data.tlsScan.issues.map(issue => `<li>${issue}</li>`).join('')}
+                            </ul>
+                        ` : ''}
+                        <div class="chain-list">
+                            ${(data.tlsScan.cipherSuites || []).map(c => `
+                                <div class="chain-item">
+                                    <div class="chain-item-title">${c.insecure ? '⚠️' : (c.forwardSecrecy ? '✅' : '➖')} ${c.name}</div>
+                                    <div class="chain-item-detail">${c.version}${c.insecure ? ' · 弱加密套件' : ''}${c.forwardSecrecy ? '' : ' · 无前向保密'}</div>
+                                </div>
+                            `).join('')}
+                        </div>
+                    `}
+                </div>
+            ` : ''}
+            
+            ${data.ct ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">🔍</span>
+                        <span class="san-title">证书透明度（CT）${data.ct.publicCa ? (data.ct.compliant ? '' : `（SCT不足，至少需要 ${data.ct.required} 个）`) : '（非公共CA，不要求）'}</span>
+                        <span class="san-count">共 ${data.ct.count} 个SCT</span>
+                    </div>
+                    ${data.ct.errors && data.ct.errors.length > 0 ? `
+                        <ul class="verify-errors">
+                            ${data.ct.errors.map(err => `<li>${err}</li>`).join('')}
+                        </ul>
+                    ` : ''}
+                    <div class="chain-list">
+                        ${(data.ct.scts || []).map(sct => `
+                            <div class="chain-item">
+                                <div class="chain-item-title">${sct.logName || '未知日志'}${sct.logOperator ? ` · ${sct.logOperator}` : ''}</div>
+                                <div class="chain-item-detail">时间：${sct.timestamp} · 来源：${{ 'embedded': '证书内嵌', 'tls': 'TLS扩展', 'ocsp': 'OCSP响应' }[sct.source] || sct.source}</div>
+                                <div class="chain-item-detail serial-value">日志ID：${sct.logId}</div>
+                            </div>
+                        `).join('')}
+                    </div>
+                </div>
+            ` : ''}
+            
+            ${data.caa ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">🏷️</span>
+                        <span class="san-title">CAA记录${data.caa.error ? '（查询失败）' : (data.caa.permitted ? '' : '（不允许当前颁发者）')}</span>
+                        <span class="san-count">${data.caa.domain ? `生效于 ${data.caa.domain}` : '未设置'}</span>
+                    </div>
+                    ${data.caa.error || data.caa.reason ? `
+                        <ul class="verify-errors">
+                            <li>${data.caa.error || data.caa.reason}</li>
+                        </ul>
+                    ` : ''}
+                    <div class="chain-list">
+                        <div class="chain-item">
+                            <div class="chain-item-title">颁发者：${data.caa.issuerOrg || 'N/A'}</div>
+                            ${(data.caa.records || []).map(record => `
+                                <div class="chain-item-detail serial-value">${record.flags} ${record.tag} "${record.value}"</div>
+                            `).join('')}
+                        </div>
+                    </div>
+                </div>
+            ` : ''}
+            
+            ${data.dane ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">📌</span>
+                        <span class="san-title">DANE/TLSA（${data.dane.name}）${data.dane.error ? '（查询失败）' : (data.dane.records && data.dane.records.length > 0 ? (data.dane.valid ? '' : '（校验失败）') : '')}</span>
+                        <span class="san-count">${data.dane.records && data.dane.records.length > 0 ? `共 ${data.dane.records.length} 条` : '未发布TLSA记录'}</span>
+                    </div>
+                    ${data.dane.error ? `
+                        <ul class="verify-errors">
+                            <li>${data.dane.error}</li>
+                        </ul>
+                    ` : ''}
+                    <div class="chain-list">
+                        ${(data.dane.records || []).map(record => `
+                            <div class="chain-item">
+                                <div class="chain-item-title">${record.pass ? '✅' : '❌'} ${record.usage} ${record.selector} ${record.matchingType}（${record.description}）</div>
+                                <div class="chain-item-detail">${record.pass ? `匹配：${record.matched}` : record.reason}</div>
+                                <div class="chain-item-detail serial-value">${record.data}</div>
+                            </div>
+                        `).join('')}
+                    </div>
+                </div>
+            ` : ''}
+            
+            ${data.http ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">🌍</span>
+                        <span class="san-title">HTTP检查${data.http.error ? '（请求失败）' : ''}</span>
+                        <span class="san-count">${data.http.statusCode ? `状态码 ${data.http.statusCode}` : ''}</span>
+                    </div>
+                    ${data.http.error ? `
+                        <ul class="verify-errors">
+                            <li>${data.http.error}</li>
+                        </ul>
+                    ` : ''}
+                    <div class="chain-list">
+                        ${!data.http.error ? `
+                            <div class="chain-item">
+                                <div class="chain-item-title">${data.http.hsts ? (data.http.hsts.maxAge > 0 ? '✅' : '⚠️') : '❌'} HSTS</div>
+                                ${data.http.hsts ? `
+                                    <div class="chain-item-detail">max-age：${data.http.hsts.maxAge} 秒（约 ${Math.floor(data.http.hsts.maxAge / 86400)} 天）${data.http.hsts.includeSubDomains ? ' · includeSubDomains' : ''}${data.http.hsts.preload ? ' · preload' : ''}${data.http.hsts.preloadReady ? ' · 满足预加载要求' : ''}</div>
+                                    <div class="chain-item-detail serial-value">${data.http.hsts.header}</div>
+                                ` : '<div class="chain-item-detail">未设置 Strict-Transport-Security 响应头</div>'}
+                            </div>
+                            <div class="chain-item">
+                                <div class="chain-item-title">重定向</div>
+                                <div class="chain-item-detail">${data.http.url}${(data.http.redirects || []).map(url => ` → ${url}`).join('')}</div>
+                            </div>
+                        ` : ''}
+                        ${data.http.finalCert ? `
+                            <div class="chain-item">
+                                <div class="chain-item-title">${data.http.finalCert.chainValid && data.http.finalCert.hostnameMatch && data.http.finalCert.daysRemaining > 0 ? '✅' : '❌'} 最终目标证书：${data.http.finalCert.host}</div>
+                                <div class="chain-item-detail">主体：${data.http.finalCert.subject || 'N/A'} · 颁发者：${data.http.finalCert.issuer || 'N/A'}</div>
+                                <div class="chain-item-detail">过期：${data.http.finalCert.notAfter}（剩余 ${data.http.finalCert.daysRemaining} 天）</div>
+                                ${(data.http.finalCert.verifyErrors || []).map(e => `<div class="chain-item-detail">${e}</div>`).join('')}
+                            </div>
+                        ` : ''}
+                        ${data.http.httpRedirect ? `
+                            <div class="chain-item">
+                                <div class="chain-item-title">${data.http.httpRedirect.toHttps ? '✅' : '⚠️'} 80端口</div>
+                                <div class="chain-item-detail">${data.http.httpRedirect.error || (data.http.httpRedirect.location ? `${data.http.httpRedirect.statusCode} → ${data.http.httpRedirect.location}` : `${data.http.httpRedirect.statusCode}（未重定向到HTTPS）`)}</div>
+                            </div>
+                        ` : ''}
+                    </div>
+                </div>
+            ` : ''}
+            
+            ${data.chain && data.chain.length > 0 ? `
+                <div class="chain-section">
+                    <div class="san-header">
+                        <span class="san-icon">🔗</span>
+                        <span class="san-title">证书链（链中最早过期：${data.chainNotAfter}）</span>
+                        <span class="san-count">共 ${data.chain.length} 个</span>
+                    </div>
+                    ${data.chainIssues && data.chainIssues.length > 0 ? `
+                        <ul class="verify-errors">
+                            ${data.chainIssues.map(issue => `<li>${issue}</li>`).join('')}
+                        </ul>
+                    ` : ''}
+                    <div class="chain-list">
+                        ${data.chain.map(c => `
+                            <div class="chain-item">
+                                <div class="chain-item-title">#${c.position} ${c.role === 'leaf' ? '叶子证书' : c.role === 'root' ? '根证书' : '中间证书'} · ${c.subject}</div>
+                                <div class="chain-item-detail">颁发者：${c.issuer}</div>
+                                <div class="chain-item-detail">有效期：${c.notBefore} ~ ${c.notAfter}（剩余 ${c.daysRemaining} 天）</div>
+                                <div class="chain-item-detail">密钥：${c.keyAlgorithm}</div>
+                                <div class="chain-item-detail serial-value">SHA-256：${c.fingerprintSha256}</div>
+                            </div>
+                        `).join('')}
+                    </div>
+                </div>
+            ` : ''}
+            
+            ${(data.sanDomains || []).length + (data.sanIps || []).length > 0 ? `
                 <div class="san-section">
                     <div class="san-header">
                         <span class="san-icon">🌐</span>
                         <span class="san-title">SAN域名列表（该证书支持的所有域名）</span>
-                        <span class="san-count">共 ${data.sanDomains.length} 个</span>
+                        <span class="san-count">共 ${(data.sanDomains || []).length + (data.sanIps || []).length} 个</span>
+                        <button class="btn-secondary" onclick="showCoverage('${formatTarget(data.domain, data.port, data.protocol)}')">🎯 覆盖检查</button>
                     </div>
                     <div class="san-list">
-                        ${data.sanDomains.map(domain => `
+                        ${(data.sanDomains || []).map(domain => `
                             <span class="san-item">${domain}</span>
                         `).join('')}
+                        ${(data.sanIps || []).map(ip => `
+                            <span class="san-item">IP: ${ip}</span>
+                        `).join('')}
                     </div>
                 </div>
             ` : ''}
@@ -497,7 +979,8 @@ window.batchCheckCerts = async function() {
     try {
         const result = await BatchCheckCertificates(domains);
         
-        if (result.success) {
+        // 全部输入无效时也展示逐行的校验错误
+        if (result.success || (result.errors && result.errors.length > 0)) {
             showBatchResults(result);
         } else {
             showError(result.message || '批量查询失败');
@@ -512,7 +995,8 @@ window.batchCheckCerts = async function() {
 
 // 显示批量查询结果
 function showBatchResults(result) {
-    const { total, results, errors } = result;
+    const { total, errors } = result;
+    const results = result.results || [];
     
     let html = `
         <div class="batch-summary">
@@ -539,13 +1023,19 @@ function showBatchResults(result) {
                 'safe': '安全',
                 'warning': '即将过期',
                 'danger': '即将过期',
-                'expired': '已过期'
+                'expired': '已过期',
+                'revoked': '已吊销',
+                'untrusted': '不受信任',
+                'mismatch': '域名不匹配'
             };
             const statusIcon = {
                 'safe': '✅',
                 'warning': '⚠️',
                 'danger': '⚠️',
-                'expired': '❌'
+                'expired': '❌',
+                'revoked': '⛔',
+                'untrusted': '🚫',
+                'mismatch': '🚫'
             };
             
             html += `
@@ -580,50 +1070,114 @@ function showBatchResults(result) {
     resultCard.classList.add('fade-in');
 }
 
-// 加载历史记录
-window.loadHistory = async function() {
+// 历史记录下一页的游标
+let historyCursor = '';
+
+// 读取历史记录筛选条件
+function readHistoryQuery() {
+    const status = document.getElementById('historyStatus').value;
+    return {
+        domain: document.getElementById('historyDomain').value.trim(),
+        statuses: status ? status.split(',') : [],
+        issuer: document.getElementById('historyIssuer').value.trim(),
+        from: document.getElementById('historyFrom').value,
+        to: document.getElementById('historyTo').value,
+        keyAlgorithm: document.getElementById('historyKeyAlgorithm').value,
+        signatureAlgorithm: document.getElementById('historySignatureAlgorithm').value,
+        weakOnly: document.getElementById('historyWeakOnly').checked,
+        limit: 100
+    };
+}
+
+// 渲染一条历史记录
+function renderHistoryItem(cert) {
+    const statusClass = `status-${cert.status}`;
+    const statusText = {
+        'safe': '安全',
+        'warning': '即将过期',
+        'danger': '即将过期',
+        'expired': '已过期',
+        'revoked': '已吊销',
+        'untrusted': '不受信任',
+        'mismatch': '域名不匹配'
+    };
+    
+    return `
+        <div class="history-item">
+            <div class="history-item-header">
+                <span class="history-domain">${cert.domain}</span>
+                <span class="history-status ${statusClass}">${statusText[cert.status]}</span>
+            </div>
+            <div class="history-item-details">
+                <span>📅 查询时间：${cert.queryTime || '未知'}</span>
+                ${formatProbeRoute(cert) ? `<span>🔀 ${formatProbeRoute(cert)}</span>` : ''}
+                ${cert.publicKeyAlgorithm ? `<span>🔑 ${cert.publicKeyAlgorithm} ${cert.publicKeySize} · ${cert.signatureAlgorithm}</span>` : ''}
+                ${cert.weakFlags && cert.weakFlags.length > 0 ? `<span>⚠️ ${cert.weakFlags.join('；')}</span>` : ''}
+                <span>⏰ 过期时间：${cert.notAfter}</span>
+                <span class="days-info ${statusClass}">⭐ 剩余 ${cert.daysRemaining} 天</span>
+                <button class="btn-secondary" onclick="showHistoryRecord(${cert.id})">🔍 详情</button>
+            </div>
+        </div>
+    `;
+}
+
+// 加载历史记录（append 为 true 时加载下一页）
+window.loadHistory = async function(append = false) {
     const historyContent = document.getElementById('historyContent');
-    historyContent.innerHTML = '<p class="empty-hint">正在加载...</p>';
+    if (!append) {
+        historyCursor = '';
+        historyContent.innerHTML = '<p class="empty-hint">正在加载...</p>';
+    }
     
     try {
-        const result = await GetHistory(100);
+        const result = await QueryHistory({...readHistoryQuery(), cursor: historyCursor});
+        if (!result.success) {
+            historyContent.innerHTML = `<p class="error-hint">❌ 加载失败：${result.message || result.error}</p>`;
+            return;
+        }
+        historyCursor = result.nextCursor || '';
         
-        if (result.success && result.records && result.records.length > 0) {
-            let html = '<div class="history-list">';
-            result.records.forEach(cert => {
-                const statusClass = `status-${cert.status}`;
-                const statusText = {
-                    'safe': '安全',
-                    'warning': '即将过期',
-                    'danger': '即将过期',
-                    'expired': '已过期'
-                };
-                
-                html += `
-                    <div class="history-item">
-                        <div class="history-item-header">
-                            <span class="history-domain">${cert.domain}</span>
-                            <span class="history-status ${statusClass}">${statusText[cert.status]}</span>
-                        </div>
-                        <div class="history-item-details">
-                            <span>📅 查询时间：${cert.queryTime || '未知'}</span>
-                            <span>⏰ 过期时间：${cert.notAfter}</span>
-                            <span class="days-info ${statusClass}">⭐ 剩余 ${cert.daysRemaining} 天</span>
-                        </div>
-                    </div>
-                `;
-            });
-            html += '</div>';
-            historyContent.innerHTML = html;
-        } else {
-            historyContent.innerHTML = '<p class="empty-hint">📂 暂无查询历史</p>';
+        let list = historyContent.querySelector('.history-list');
+        if (!append || !list) {
+            if (result.records.length === 0) {
+                historyContent.innerHTML = '<p class="empty-hint">📂 暂无查询历史</p>';
+                return;
+            }
+            historyContent.innerHTML = '<p class="history-summary"></p><div class="history-list"></div><div class="history-more"></div>';
+            list = historyContent.querySelector('.history-list');
         }
+        list.insertAdjacentHTML('beforeend', result.records.map(renderHistoryItem).join(''));
+        
+        const shown = list.querySelectorAll('.history-item').length;
+        historyContent.querySelector('.history-summary').textContent = `共 ${result.total} 条记录，已显示 ${shown} 条`;
+        historyContent.querySelector('.history-more').innerHTML = historyCursor
+            ? '<button class="btn-secondary" onclick="loadHistory(true)">⬇️ 加载更多</button>'
+            : '';
     } catch (err) {
         historyContent.innerHTML = `<p class="error-hint">❌ 加载失败：${err.message}</p>`;
         console.error(err);
     }
 };
 
+// 查看历史记录的完整信息（证书链、SAN、查询时的检查结果）
+window.showHistoryRecord = async function(id) {
+    try {
+        const result = await GetHistoryRecord(id);
+        if (!result.success) {
+            showToast(`❌ ${result.message || result.error}`);
+            return;
+        }
+        document.querySelector('[data-tab="single"]').click();
+        showSuccess(result.data);
+        if (result.message !== '查询成功') {
+            showToast(`ℹ️ ${result.message}`);
+        }
+    } catch (err) {
+        showToast('❌ 加载失败：' + err.message);
+        console.error(err);
+    }
+};
+
 // 清空历史记录确认
 window.clearHistoryConfirm = function() {
     if (confirm('确定要清空所有历史记录吗？此操作不可恢复！')) {
@@ -725,19 +1279,26 @@ function renderWatchedDomains(domains) {
     
     domains.forEach(watched => {
         const cert = watched.certInfo;
+        const target = formatTarget(watched.domain, watched.port, watched.protocol);
         if (cert) {
             const statusClass = `status-${cert.status}`;
             const statusText = {
                 'safe': '安全',
                 'warning': '即将过期',
                 'danger': '即将过期',
-                'expired': '已过期'
+                'expired': '已过期',
+                'revoked': '已吊销',
+                'untrusted': '不受信任',
+                'mismatch': '域名不匹配'
             };
             const statusIcon = {
                 'safe': '✅',
                 'warning': '⚠️',
                 'danger': '⚠️',
-                'expired': '❌'
+                'expired': '❌',
+                'revoked': '⛔',
+                'untrusted': '🚫',
+                'mismatch': '🚫'
             };
             
             // 计算证书总有效期和进度条百分比
@@ -773,7 +1334,7 @@ function renderWatchedDomains(domains) {
             }
             
             html += `
-                <div class="watched-item ${statusClass}" data-domain="${watched.domain}" data-id="${watched.id}">
+                <div class="watched-item ${statusClass}" data-domain="${target}" data-id="${watched.id}">
                     <!-- 批量选择复选框 -->
                     <div class="batch-checkbox" style="display: ${batchMode ? 'block' : 'none'};">
                         <input type="checkbox" class="domain-checkbox" data-id="${watched.id}" onchange="toggleDomainSelection(${watched.id})">
@@ -781,26 +1342,30 @@ function renderWatchedDomains(domains) {
                     
                     <div class="watched-item-header">
                         <div class="watched-domain-info">
-                            <span class="watched-domain">${watched.domain}</span>
+                            <span class="watched-domain">${target}</span>
+                            ${watched.unicodeDomain ? `<span class="watched-nickname">${watched.unicodeDomain}</span>` : ''}
                             ${watched.nickname ? `<span class="watched-nickname">${watched.nickname}</span>` : ''}
                         </div>
                         <div class="watched-actions-inline">
-                            <button class="btn-icon btn-detect" onclick="quickCheckDomain('${watched.domain}')" title="立即检测">
+                            <button class="btn-icon btn-detect" onclick="quickCheckDomain('${target}')" title="立即检测">
                                 <span>🔍</span>
                             </button>
-                            <button class="btn-icon ${watched.notifyEnabled ? 'btn-notify-active' : ''}" onclick="showNotifySettings(${watched.id}, '${watched.domain}', ${watched.notifyEnabled}, ${watched.notifyThreshold})" title="通知设置">
+                            <button class="btn-icon ${watched.notifyEnabled ? 'btn-notify-active' : ''}" onclick="showNotifySettings(${watched.id}, '${target}', ${watched.notifyEnabled}, ${watched.notifyThreshold})" title="通知设置">
                                 <span>🔔</span>
                             </button>
-                            <button class="btn-icon ${watched.isManual ? 'btn-manual-active' : ''}" onclick="showManualCertEdit(${watched.id}, '${watched.domain}', ${watched.isManual}, '${watched.manualExpireDate || ''}')" title="${watched.isManual ? '手动模式' : '手动录入'}">
+                            <button class="btn-icon ${watched.isManual ? 'btn-manual-active' : ''}" onclick="showManualCertEdit(${watched.id}, '${target}', ${watched.isManual}, '${watched.manualExpireDate || ''}')" title="${watched.isManual ? '手动模式' : '手动录入'}">
                                 <span>✍️</span>
                             </button>
-                            <button class="btn-icon btn-detail" onclick="toggleDetails('${watched.domain}')" title="查看详情" data-domain="${watched.domain}">
+                            <button class="btn-icon btn-detail" onclick="toggleDetails('${target}')" title="查看详情" data-domain="${target}">
                                 <span class="detail-icon">🔽</span>
                             </button>
-                            <button class="btn-icon" onclick="editWatchedNickname(${watched.id}, '${watched.domain}', '${watched.nickname || ''}')" title="编辑备注">
+                            <button class="btn-icon" onclick="showDomainTimeline(${watched.id})" title="证书变更记录">
+                                <span>🕒</span>
+                            </button>
+                            <button class="btn-icon" onclick="editWatchedNickname(${watched.id}, '${target}', '${watched.nickname || ''}')" title="编辑备注">
                                 <span>✏️</span>
                             </button>
-                            <button class="btn-icon btn-icon-danger" onclick="removeWatchedConfirm(${watched.id}, '${watched.domain}')" title="移除关注">
+                            <button class="btn-icon btn-icon-danger" onclick="removeWatchedConfirm(${watched.id}, '${target}')" title="移除关注">
                                 <span>🗑️</span>
                             </button>
                         </div>
@@ -828,10 +1393,34 @@ function renderWatchedDomains(domains) {
                             <span class="detail-label">⏰ 最后检查</span>
                             <span class="detail-value">${watched.lastCheckTime || '未检查'}</span>
                         </div>
+                        ${watched.tlsGrade ? `
+                        <div class="watched-detail-item">
+                            <span class="detail-label">🛡️ TLS评级</span>
+                            <span class="detail-value">${watched.tlsGrade}</span>
+                        </div>
+                        ` : ''}
+                        ${watched.caaWarning ? `
+                        <div class="watched-detail-item">
+                            <span class="detail-label">⚠️ CAA</span>
+                            <span class="detail-value">${watched.caaWarning}</span>
+                        </div>
+                        ` : ''}
+                        ${watched.hstsWarning ? `
+                        <div class="watched-detail-item">
+                            <span class="detail-label">⚠️ HSTS</span>
+                            <span class="detail-value">${watched.hstsWarning}</span>
+                        </div>
+                        ` : ''}
+                        ${watched.changes && watched.changes.length > 0 ? `
+                        <div class="watched-detail-item">
+                            <span class="detail-label">${watched.changes.some(c => c.unexpected) ? '⚠️' : '🔄'} 证书变更</span>
+                            <span class="detail-value">${watched.changes.map(c => c.description).join('；')}</span>
+                        </div>
+                        ` : ''}
                     </div>
                     
                     <!-- 详细信息卡片（默认隐藏） -->
-                    <div class="cert-detail-card" id="detail-${watched.domain}" style="display: none;">
+                    <div class="cert-detail-card" id="detail-${target}" style="display: none;">
                         <div class="detail-card-header">
                             <span class="detail-card-title">📜 证书详细信息</span>
                         </div>
@@ -870,7 +1459,7 @@ function renderWatchedDomains(domains) {
             `;
         } else {
             html += `
-                <div class="watched-item error" data-domain="${watched.domain}" data-id="${watched.id}">
+                <div class="watched-item error" data-domain="${target}" data-id="${watched.id}">
                     <!-- 批量选择复选框 -->
                     <div class="batch-checkbox" style="display: ${batchMode ? 'block' : 'none'};">
                         <input type="checkbox" class="domain-checkbox" data-id="${watched.id}" onchange="toggleDomainSelection(${watched.id})">
@@ -878,17 +1467,18 @@ function renderWatchedDomains(domains) {
                     
                     <div class="watched-item-header">
                         <div class="watched-domain-info">
-                            <span class="watched-domain">${watched.domain}</span>
+                            <span class="watched-domain">${target}</span>
+                            ${watched.unicodeDomain ? `<span class="watched-nickname">${watched.unicodeDomain}</span>` : ''}
                             ${watched.nickname ? `<span class="watched-nickname">${watched.nickname}</span>` : ''}
                         </div>
                         <div class="watched-actions-inline">
-                            <button class="btn-icon btn-detect" onclick="quickCheckDomain('${watched.domain}')" title="立即检测">
+                            <button class="btn-icon btn-detect" onclick="quickCheckDomain('${target}')" title="立即检测">
                                 <span>🔍</span>
                             </button>
-                            <button class="btn-icon ${watched.isManual ? 'btn-manual-active' : ''}" onclick="showManualCertEdit(${watched.id}, '${watched.domain}', ${watched.isManual}, '${watched.manualExpireDate || ''}')" title="${watched.isManual ? '手动模式' : '手动录入'}">
+                            <button class="btn-icon ${watched.isManual ? 'btn-manual-active' : ''}" onclick="showManualCertEdit(${watched.id}, '${target}', ${watched.isManual}, '${watched.manualExpireDate || ''}')" title="${watched.isManual ? '手动模式' : '手动录入'}">
                                 <span>✍️</span>
                             </button>
-                            <button class="btn-icon btn-icon-danger" onclick="removeWatchedConfirm(${watched.id}, '${watched.domain}')" title="移除关注">
+                            <button class="btn-icon btn-icon-danger" onclick="removeWatchedConfirm(${watched.id}, '${target}')" title="移除关注">
                                 <span>🗑️</span>
                             </button>
                         </div>
@@ -1113,6 +1703,7 @@ function applyFilters() {
     let filteredDomains = currentWatchedDomains.filter(watched => {
         if (!searchTerm) return true;
         return watched.domain.toLowerCase().includes(searchTerm) || 
+               (watched.unicodeDomain && watched.unicodeDomain.toLowerCase().includes(searchTerm)) ||
                (watched.nickname && watched.nickname.toLowerCase().includes(searchTerm));
     });
     
@@ -1124,7 +1715,7 @@ function applyFilters() {
             case 'days-desc':
                 return (b.certInfo?.daysRemaining || 0) - (a.certInfo?.daysRemaining || 0);
             case 'status':
-                const statusOrder = {'expired': 0, 'danger': 1, 'warning': 2, 'safe': 3};
+                const statusOrder = {'revoked': 0, 'expired': 1, 'mismatch': 2, 'untrusted': 3, 'danger': 4, 'warning': 5, 'safe': 6};
                 return (statusOrder[a.certInfo?.status] || 4) - (statusOrder[b.certInfo?.status] || 4);
             case 'domain':
                 return a.domain.localeCompare(b.domain);
@@ -1468,7 +2059,7 @@ window.batchRefresh = async function() {
     
     for (const domain of selectedDomains) {
         try {
-            const result = await RefreshWatchedDomain(domain.domain);
+            const result = await RefreshWatchedDomain(formatTarget(domain.domain, domain.port, domain.protocol));
             if (result.success) {
                 successCount++;
             } else {
@@ -1546,7 +2137,10 @@ function exportDomainsToCSV(domains, filename) {
                 'safe': '安全',
                 'warning': '警告',
                 'danger': '危险',
-                'expired': '已过期'
+                'expired': '已过期',
+                'revoked': '已吊销',
+                'untrusted': '不受信任',
+                'mismatch': '域名不匹配'
             };
             return [
                 d.domain,
@@ -1800,19 +2394,30 @@ function showNotificationDialog(items) {
         'safe': '✅',
         'warning': '⚠️',
         'danger': '🔴',
-        'expired': '❌'
+        'expired': '❌',
+        'revoked': '⛔',
+        'untrusted': '🚫',
+        'mismatch': '🚫'
     };
     
     const statusText = {
         'safe': '安全',
         'warning': '警告',
         'danger': '危险',
-        'expired': '已过期'
+        'expired': '已过期',
+        'revoked': '已吊销',
+        'untrusted': '不受信任',
+        'mismatch': '域名不匹配'
     };
     
     // 构建通知列表HTML
     let notificationListHtml = items.map(item => {
-        const displayName = item.nickname ? `${item.nickname} (${item.domain})` : item.domain;
+        let displayName = item.nickname ? `${item.nickname} (${item.domain})` : item.domain;
+        if (item.kind === 'client_cert') {
+            displayName = `🔑 客户端证书：${item.domain}`;
+        } else if (item.kind === 'cert_change') {
+            displayName = `🔄 证书变更：${displayName}`;
+        }
         const statusClass = `status-${item.status}`;
         
         return `
@@ -1832,6 +2437,12 @@ function showNotificationDialog(items) {
                     <span class="notification-label">预警阈值：</span>
                     <span class="notification-value">${item.threshold} 天</span>
                 </div>
+                ${item.reason ? `
+                <div class="notification-info">
+                    <span class="notification-label">原因：</span>
+                    <span class="notification-value">${item.reason}</span>
+                </div>
+                ` : ''}
             </div>
         `;
     }).join('');
@@ -1877,10 +2488,17 @@ function showNotificationDialog(items) {
     
     // 存储overlay以便关闭
     window.currentNotificationOverlay = overlay;
+    // 证书变更通知在对话框关闭（用户已看到）后确认
+    window.pendingCertEventIds = items.flatMap(item => item.eventIds || []);
 }
 
 // 关闭通知对话框
 window.closeNotificationDialog = function() {
+    const eventIds = window.pendingCertEventIds || [];
+    window.pendingCertEventIds = [];
+    if (eventIds.length > 0) {
+        AcknowledgeCertEvents(eventIds).catch(err => console.error('确认证书变更通知失败:', err));
+    }
     if (window.currentNotificationOverlay) {
         window.currentNotificationOverlay.classList.remove('show');
         setTimeout(() => {
@@ -1913,6 +2531,140 @@ window.addEventListener('load', () => {
 let autoRefreshTimer = null;
 
 // 自动刷新配置对话框
+// 打开只有关闭按钮的信息对话框
+function showInfoDialog(title, content) {
+    const overlay = document.createElement('div');
+    overlay.className = 'dialog-overlay';
+    
+    const dialog = document.createElement('div');
+    dialog.className = 'custom-dialog';
+    dialog.style.maxWidth = '600px';
+    dialog.innerHTML = `
+        <div class="dialog-title">${title}</div>
+        <div class="dialog-content" style="max-height: 60vh; overflow-y: auto;">${content}</div>
+        <div class="dialog-buttons">
+            <button class="dialog-btn dialog-btn-confirm">关闭</button>
+        </div>
+    `;
+    dialog.querySelector('.dialog-btn-confirm').onclick = () => overlay.remove();
+    
+    overlay.appendChild(dialog);
+    document.body.appendChild(overlay);
+}
+
+// 启动时检查数据库初始化和迁移状态，失败时提示用户（历史记录、关注域名等功能不可用）
+async function checkSchemaStatus() {
+    try {
+        const status = await GetSchemaStatus();
+        if (status.success) {
+            return;
+        }
+        showInfoDialog('<span>❌</span> 数据库初始化失败', `
+            <div class="setting-info">${status.error || status.message}</div>
+            ${status.dbPath ? `<div class="setting-info">数据库文件：${status.dbPath}</div>` : ''}
+            ${status.backupPath ? `<div class="setting-info">迁移前的备份：${status.backupPath}</div>` : ''}
+            <div class="setting-info">证书查询仍可使用，但历史记录、关注域名和设置无法保存。请检查数据目录权限或使用新版本程序后重新启动。</div>
+        `);
+    } catch (err) {
+        console.error('检查数据库状态失败:', err);
+    }
+}
+
+checkSchemaStatus();
+
+// 关注域名的证书变更时间线
+window.showDomainTimeline = async function(id) {
+    try {
+        const result = await GetDomainTimeline(id);
+        if (!result.success) {
+            showToast(`❌ ${result.message || result.error}`);
+            return;
+        }
+        const snapshot = result.snapshot;
+        showInfoDialog(`<span>🕒</span> 证书变更记录：${result.domain}`, `
+            ${snapshot ? `
+            <div class="setting-info">
+                当前证书：${snapshot.issuer || '（无颁发者）'} · 序列号 ${snapshot.serialNumber} · 过期 ${snapshot.notAfter}<br>
+                自 ${snapshot.firstSeenTime} 开始记录，最近检查 ${snapshot.checkedTime}
+            </div>
+            ` : '<div class="setting-info">尚未检查过该域名的证书</div>'}
+            <div class="chain-list">
+                ${result.events.map(e => `
+                    <div class="chain-item">
+                        <span>${e.unexpected ? '⚠️' : (e.kind === 'first_seen' ? '📌' : '🔄')}</span>
+                        <span>${e.eventTime}</span>
+                        <span>${e.description}</span>
+                    </div>
+                `).join('') || '<p class="empty-hint">暂无变更记录</p>'}
+            </div>
+        `);
+    } catch (err) {
+        showToast('❌ 加载失败：' + err.message);
+        console.error(err);
+    }
+};
+
+// SAN覆盖检查
+window.showCoverage = async function(target) {
+    try {
+        const result = await CheckCoverage(target);
+        if (!result.success) {
+            showToast(`❌ ${result.message || result.error}`);
+            return;
+        }
+        showInfoDialog(`<span>🎯</span> SAN覆盖检查：${result.name}`, `
+            <div class="setting-info">
+                <span>${result.covered ? '✅' : '❌'}</span> ${result.message}
+            </div>
+            <div class="chain-list">
+                ${(result.sans || []).map(san => `
+                    <div class="chain-item">
+                        <div class="chain-item-title">${san.covers ? '✅' : '➖'} ${san.type === 'ip' ? 'IP: ' : ''}${san.name}</div>
+                        ${san.reason ? `<div class="chain-item-detail">${san.reason}</div>` : ''}
+                    </div>
+                `).join('')}
+            </div>
+        `);
+    } catch (err) {
+        showToast('❌ 覆盖检查失败：' + err);
+    }
+};
+
+// 共用证书报告
+window.showSharedCertReport = async function() {
+    showToast('正在刷新关注域名并分析证书...');
+    try {
+        const result = await GetSharedCertificateReport();
+        if (!result.success) {
+            showToast(`❌ ${result.message || result.error}`);
+            return;
+        }
+        showInfoDialog('<span>🧩</span> 共用证书', `
+            <div class="setting-info">
+                <span>💡</span> ${result.message}
+            </div>
+            <div class="chain-list">
+                ${result.groups.map(group => `
+                    <div class="chain-item">
+                        <div class="chain-item-title">${group.wildcard ? '✳️' : '📜'} ${group.subject || 'N/A'} · ${group.domains.length} 个目标</div>
+                        <div class="chain-item-detail">颁发者：${group.issuer} · 过期：${group.notAfter}（剩余 ${group.daysRemaining} 天）</div>
+                        <div class="chain-item-detail">${group.domains.join('、')}</div>
+                        ${group.uncovered && group.uncovered.length > 0 ? `<div class="chain-item-detail">⚠️ SAN不覆盖：${group.uncovered.join('、')}</div>` : ''}
+                        <div class="chain-item-detail serial-value">${group.fingerprintSha256}</div>
+                    </div>
+                `).join('')}
+            </div>
+            ${result.failed && result.failed.length > 0 ? `
+                <ul class="verify-errors">
+                    <li>查询失败：${result.failed.join('、')}</li>
+                </ul>
+            ` : ''}
+        `);
+    } catch (err) {
+        showToast('❌ 生成报告失败：' + err);
+    }
+};
+
 window.showAutoRefreshSettings = function() {
     const currentInterval = localStorage.getItem('autoRefreshInterval') || '0';
     const isEnabled = currentInterval !== '0';
@@ -2,32 +2,92 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function AcknowledgeCertEvents(arg1:Array<number>):Promise<void>;
+
+export function AddClientCertificate(arg1:main.ClientCertificateRequest):Promise<main.QueryResult>;
+
+export function AddTrustStore(arg1:string,arg2:string):Promise<main.QueryResult>;
+
 export function AddWatchedDomain(arg1:string,arg2:string):Promise<main.QueryResult>;
 
 export function BatchCheckCertificates(arg1:string):Promise<main.BatchQueryResult>;
 
+export function CancelProbes():Promise<void>;
+
 export function CheckCertificate(arg1:string):Promise<main.QueryResult>;
 
+export function CheckCertificateWithOptions(arg1:string,arg2:main.ProbeOptions):Promise<main.QueryResult>;
+
+export function CheckCoverage(arg1:string):Promise<main.CoverageResult>;
+
 export function CheckNotifications():Promise<main.NotificationResult>;
 
 export function ClearHistory():Promise<void>;
 
 export function DisableManualMode(arg1:number):Promise<void>;
 
+export function GetCRLStatus():Promise<main.CRLStatusResult>;
+
+export function GetCTLogList():Promise<main.CTLogListResult>;
+
+export function GetClientCertificates():Promise<main.ClientCertificatesResult>;
+
+export function GetDomainTimeline(arg1:number):Promise<main.DomainTimelineResult>;
+
 export function GetHistory(arg1:number):Promise<main.HistoryQueryResult>;
 
+export function GetHistoryRecord(arg1:number):Promise<main.QueryResult>;
+
+export function GetProxySettings():Promise<main.ProxySettingsResult>;
+
+export function GetRetentionPolicy():Promise<main.RetentionPolicyResult>;
+
+export function GetSchemaStatus():Promise<main.SchemaStatus>;
+
+export function GetSettings():Promise<main.SettingsResult>;
+
+export function GetSharedCertificateReport():Promise<main.SharedCertReportResult>;
+
+export function GetTrustStores():Promise<main.TrustStoresResult>;
+
 export function GetWatchedDomains():Promise<main.WatchedDomainsResult>;
 
 export function ImportDomainsFromText(arg1:string):Promise<main.ImportDomainsResult>;
 
+export function PruneHistory(arg1:boolean):Promise<main.PruneResult>;
+
+export function QueryHistory(arg1:main.HistoryQuery):Promise<main.HistoryPageResult>;
+
 export function RefreshAllWatchedDomains():Promise<main.WatchedDomainsResult>;
 
 export function RefreshWatchedDomain(arg1:string):Promise<main.QueryResult>;
 
+export function RemoveClientCertificate(arg1:number):Promise<void>;
+
+export function RemoveTrustStore(arg1:number):Promise<void>;
+
 export function RemoveWatchedDomain(arg1:number):Promise<void>;
 
+export function SetTrustStoreGlobal(arg1:number,arg2:boolean):Promise<void>;
+
+export function UpdateCTLogList(arg1:string):Promise<main.CTLogListResult>;
+
+export function UpdateClientCertNotifySettings(arg1:number,arg2:boolean,arg3:number):Promise<void>;
+
 export function UpdateManualCertInfo(arg1:number,arg2:string,arg3:string):Promise<void>;
 
 export function UpdateNotifySettings(arg1:number,arg2:boolean,arg3:number):Promise<void>;
 
+export function UpdateRetentionPolicy(arg1:main.RetentionPolicy):Promise<void>;
+
+export function UpdateSettings(arg1:main.Settings):Promise<void>;
+
+export function UpdateWatchedDomainClientCert(arg1:number,arg2:number):Promise<void>;
+
 export function UpdateWatchedDomainNickname(arg1:number,arg2:string):Promise<void>;
+
+export function UpdateWatchedDomainOptions(arg1:number,arg2:main.ProbeOptions):Promise<void>;
+
+export function UpdateWatchedDomainProtocol(arg1:number,arg2:string):Promise<void>;
+
+export function UpdateWatchedDomainTrustStore(arg1:number,arg2:number):Promise<void>;
@@ -2,6 +2,18 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcknowledgeCertEvents(arg1) {
+  return window['go']['main']['App']['AcknowledgeCertEvents'](arg1);
+}
+
+export function AddClientCertificate(arg1) {
+  return window['go']['main']['App']['AddClientCertificate'](arg1);
+}
+
+export function AddTrustStore(arg1, arg2) {
+  return window['go']['main']['App']['AddTrustStore'](arg1, arg2);
+}
+
 export function AddWatchedDomain(arg1, arg2) {
   return window['go']['main']['App']['AddWatchedDomain'](arg1, arg2);
 }
@@ -10,10 +22,22 @@ export function BatchCheckCertificates(arg1) {
   return window['go']['main']['App']['BatchCheckCertificates'](arg1);
 }
 
+export function CancelProbes() {
+  return window['go']['main']['App']['CancelProbes']();
+}
+
 export function CheckCertificate(arg1) {
   return window['go']['main']['App']['CheckCertificate'](arg1);
 }
 
+export function CheckCertificateWithOptions(arg1, arg2) {
+  return window['go']['main']['App']['CheckCertificateWithOptions'](arg1, arg2);
+}
+
+export function CheckCoverage(arg1) {
+  return window['go']['main']['App']['CheckCoverage'](arg1);
+}
+
 export function CheckNotifications() {
   return window['go']['main']['App']['CheckNotifications']();
 }
@@ -26,10 +50,54 @@ export function DisableManualMode(arg1) {
   return window['go']['main']['App']['DisableManualMode'](arg1);
 }
 
+export function GetCRLStatus() {
+  return window['go']['main']['App']['GetCRLStatus']();
+}
+
+export function GetCTLogList() {
+  return window['go']['main']['App']['GetCTLogList']();
+}
+
+export function GetClientCertificates() {
+  return window['go']['main']['App']['GetClientCertificates']();
+}
+
+export function GetDomainTimeline(arg1) {
+  return window['go']['main']['App']['GetDomainTimeline'](arg1);
+}
+
 export function GetHistory(arg1) {
   return window['go']['main']['App']['GetHistory'](arg1);
 }
 
+export function GetHistoryRecord(arg1) {
+  return window['go']['main']['App']['GetHistoryRecord'](arg1);
+}
+
+export function GetProxySettings() {
+  return window['go']['main']['App']['GetProxySettings']();
+}
+
+export function GetRetentionPolicy() {
+  return window['go']['main']['App']['GetRetentionPolicy']();
+}
+
+export function GetSchemaStatus() {
+  return window['go']['main']['App']['GetSchemaStatus']();
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetSharedCertificateReport() {
+  return window['go']['main']['App']['GetSharedCertificateReport']();
+}
+
+export function GetTrustStores() {
+  return window['go']['main']['App']['GetTrustStores']();
+}
+
 export function GetWatchedDomains() {
   return window['go']['main']['App']['GetWatchedDomains']();
 }
@@ -38,6 +106,14 @@ export function ImportDomainsFromText(arg1) {
   return window['go']['main']['App']['ImportDomainsFromText'](arg1);
 }
 
+export function PruneHistory(arg1) {
+  return window['go']['main']['App']['PruneHistory'](arg1);
+}
+
+export function QueryHistory(arg1) {
+  return window['go']['main']['App']['QueryHistory'](arg1);
+}
+
 export function RefreshAllWatchedDomains() {
   return window['go']['main']['App']['RefreshAllWatchedDomains']();
 }
@@ -46,10 +122,30 @@ export function RefreshWatchedDomain(arg1) {
   return window['go']['main']['App']['RefreshWatchedDomain'](arg1);
 }
 
+export function RemoveClientCertificate(arg1) {
+  return window['go']['main']['App']['RemoveClientCertificate'](arg1);
+}
+
+export function RemoveTrustStore(arg1) {
+  return window['go']['main']['App']['RemoveTrustStore'](arg1);
+}
+
 export function RemoveWatchedDomain(arg1) {
   return window['go']['main']['App']['RemoveWatchedDomain'](arg1);
 }
 
+export function SetTrustStoreGlobal(arg1, arg2) {
+  return window['go']['main']['App']['SetTrustStoreGlobal'](arg1, arg2);
+}
+
+export function UpdateCTLogList(arg1) {
+  return window['go']['main']['App']['UpdateCTLogList'](arg1);
+}
+
+export function UpdateClientCertNotifySettings(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateClientCertNotifySettings'](arg1, arg2, arg3);
+}
+
 export function UpdateManualCertInfo(arg1, arg2, arg3) {
   return window['go']['main']['App']['UpdateManualCertInfo'](arg1, arg2, arg3);
 }
@@ -58,6 +154,30 @@ export function UpdateNotifySettings(arg1, arg2, arg3) {
   return window['go']['main']['App']['UpdateNotifySettings'](arg1, arg2, arg3);
 }
 
+export function UpdateRetentionPolicy(arg1) {
+  return window['go']['main']['App']['UpdateRetentionPolicy'](arg1);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
+export function UpdateWatchedDomainClientCert(arg1, arg2) {
+  return window['go']['main']['App']['UpdateWatchedDomainClientCert'](arg1, arg2);
+}
+
 export function UpdateWatchedDomainNickname(arg1, arg2) {
   return window['go']['main']['App']['UpdateWatchedDomainNickname'](arg1, arg2);
 }
+
+export function UpdateWatchedDomainOptions(arg1, arg2) {
+  return window['go']['main']['App']['UpdateWatchedDomainOptions'](arg1, arg2);
+}
+
+export function UpdateWatchedDomainProtocol(arg1, arg2) {
+  return window['go']['main']['App']['UpdateWatchedDomainProtocol'](arg1, arg2);
+}
+
+export function UpdateWatchedDomainTrustStore(arg1, arg2) {
+  return window['go']['main']['App']['UpdateWatchedDomainTrustStore'](arg1, arg2);
+}
@@ -1,50 +1,1592 @@
 export namespace main {
 	
-	export class CertificateInfo {
-	    id?: number;
-	    domain: string;
+	export class HTTPRedirectInfo {
+	    statusCode?: number;
+	    location?: string;
+	    toHttps: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HTTPRedirectInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statusCode = source["statusCode"];
+	        this.location = source["location"];
+	        this.toHttps = source["toHttps"];
+	        this.error = source["error"];
+	    }
+	}
+	export class HSTSInfo {
+	    header: string;
+	    maxAge: number;
+	    includeSubDomains: boolean;
+	    preload: boolean;
+	    preloadReady: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new HSTSInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.header = source["header"];
+	        this.maxAge = source["maxAge"];
+	        this.includeSubDomains = source["includeSubDomains"];
+	        this.preload = source["preload"];
+	        this.preloadReady = source["preloadReady"];
+	    }
+	}
+	export class HTTPEndpointCert {
+	    host: string;
+	    subject: string;
 	    issuer: string;
+	    notAfter: string;
+	    daysRemaining: number;
+	    fingerprintSha256: string;
+	    chainValid: boolean;
+	    hostnameMatch: boolean;
+	    verifyErrors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new HTTPEndpointCert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.subject = source["subject"];
+	        this.issuer = source["issuer"];
+	        this.notAfter = source["notAfter"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.chainValid = source["chainValid"];
+	        this.hostnameMatch = source["hostnameMatch"];
+	        this.verifyErrors = source["verifyErrors"];
+	    }
+	}
+	export class HTTPInfo {
+	    url: string;
+	    statusCode?: number;
+	    redirects?: string[];
+	    finalUrl?: string;
+	    finalCert?: HTTPEndpointCert;
+	    hsts?: HSTSInfo;
+	    httpRedirect?: HTTPRedirectInfo;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HTTPInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.statusCode = source["statusCode"];
+	        this.redirects = source["redirects"];
+	        this.finalUrl = source["finalUrl"];
+	        this.finalCert = this.convertValues(source["finalCert"], HTTPEndpointCert);
+	        this.hsts = this.convertValues(source["hsts"], HSTSInfo);
+	        this.httpRedirect = this.convertValues(source["httpRedirect"], HTTPRedirectInfo);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TLSARecordResult {
+	    usage: number;
+	    selector: number;
+	    matchingType: number;
+	    data: string;
+	    description: string;
+	    pass: boolean;
+	    matched?: string;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TLSARecordResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.usage = source["usage"];
+	        this.selector = source["selector"];
+	        this.matchingType = source["matchingType"];
+	        this.data = source["data"];
+	        this.description = source["description"];
+	        this.pass = source["pass"];
+	        this.matched = source["matched"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class DANEInfo {
+	    name: string;
+	    records?: TLSARecordResult[];
+	    valid: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DANEInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.records = this.convertValues(source["records"], TLSARecordResult);
+	        this.valid = source["valid"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CAARecord {
+	    flags: number;
+	    tag: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CAARecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.flags = source["flags"];
+	        this.tag = source["tag"];
+	        this.value = source["value"];
+	    }
+	}
+	export class CAAInfo {
+	    domain?: string;
+	    records?: CAARecord[];
+	    issuerOrg: string;
+	    allowed?: string[];
+	    permitted: boolean;
+	    reason?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CAAInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.domain = source["domain"];
+	        this.records = this.convertValues(source["records"], CAARecord);
+	        this.issuerOrg = source["issuerOrg"];
+	        this.allowed = source["allowed"];
+	        this.permitted = source["permitted"];
+	        this.reason = source["reason"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SCTInfo {
+	    logId: string;
+	    logName?: string;
+	    logOperator?: string;
+	    timestamp: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SCTInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.logId = source["logId"];
+	        this.logName = source["logName"];
+	        this.logOperator = source["logOperator"];
+	        this.timestamp = source["timestamp"];
+	        this.source = source["source"];
+	    }
+	}
+	export class CTInfo {
+	    scts?: SCTInfo[];
+	    count: number;
+	    publicCa: boolean;
+	    required: number;
+	    compliant: boolean;
+	    errors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CTInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.scts = this.convertValues(source["scts"], SCTInfo);
+	        this.count = source["count"];
+	        this.publicCa = source["publicCa"];
+	        this.required = source["required"];
+	        this.compliant = source["compliant"];
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CipherSuiteResult {
+	    name: string;
+	    version: string;
+	    insecure: boolean;
+	    forwardSecrecy: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CipherSuiteResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.insecure = source["insecure"];
+	        this.forwardSecrecy = source["forwardSecrecy"];
+	    }
+	}
+	export class TLSScanResult {
+	    supportedVersions: string[];
+	    negotiatedVersion: string;
+	    negotiatedCipher: string;
+	    alpn?: string;
+	    supportedCurves: string[];
+	    cipherSuites: CipherSuiteResult[];
+	    grade: string;
+	    issues?: string[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TLSScanResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.supportedVersions = source["supportedVersions"];
+	        this.negotiatedVersion = source["negotiatedVersion"];
+	        this.negotiatedCipher = source["negotiatedCipher"];
+	        this.alpn = source["alpn"];
+	        this.supportedCurves = source["supportedCurves"];
+	        this.cipherSuites = this.convertValues(source["cipherSuites"], CipherSuiteResult);
+	        this.grade = source["grade"];
+	        this.issues = source["issues"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CRLCheck {
+	    certificate: string;
+	    url: string;
+	    revoked: boolean;
+	    revokedAt?: string;
+	    reason?: string;
+	    nextUpdate?: string;
+	    stale: boolean;
+	    cached: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CRLCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.certificate = source["certificate"];
+	        this.url = source["url"];
+	        this.revoked = source["revoked"];
+	        this.revokedAt = source["revokedAt"];
+	        this.reason = source["reason"];
+	        this.nextUpdate = source["nextUpdate"];
+	        this.stale = source["stale"];
+	        this.cached = source["cached"];
+	        this.error = source["error"];
+	    }
+	}
+	export class OCSPInfo {
+	    status: string;
+	    source?: string;
+	    stapled: boolean;
+	    responderUrl?: string;
+	    thisUpdate?: string;
+	    nextUpdate?: string;
+	    revokedAt?: string;
+	    revocationReason?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OCSPInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.source = source["source"];
+	        this.stapled = source["stapled"];
+	        this.responderUrl = source["responderUrl"];
+	        this.thisUpdate = source["thisUpdate"];
+	        this.nextUpdate = source["nextUpdate"];
+	        this.revokedAt = source["revokedAt"];
+	        this.revocationReason = source["revocationReason"];
+	        this.error = source["error"];
+	    }
+	}
+	export class IPProbeResult {
+	    ip: string;
+	    success: boolean;
+	    error?: string;
+	    serialNumber?: string;
+	    notAfter?: string;
+	    daysRemaining: number;
+	    fingerprintSha256?: string;
+	    status?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IPProbeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ip = source["ip"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.serialNumber = source["serialNumber"];
+	        this.notAfter = source["notAfter"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.status = source["status"];
+	    }
+	}
+	export class ChainCertificate {
+	    position: number;
+	    role: string;
 	    subject: string;
+	    issuer: string;
 	    notBefore: string;
 	    notAfter: string;
 	    daysRemaining: number;
-	    isValid: boolean;
-	    status: string;
+	    keyAlgorithm: string;
 	    serialNumber: string;
-	    version: number;
-	    queryTime?: string;
-	    sanDomains?: string[];
+	    fingerprintSha256: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChainCertificate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.position = source["position"];
+	        this.role = source["role"];
+	        this.subject = source["subject"];
+	        this.issuer = source["issuer"];
+	        this.notBefore = source["notBefore"];
+	        this.notAfter = source["notAfter"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.keyAlgorithm = source["keyAlgorithm"];
+	        this.serialNumber = source["serialNumber"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	    }
+	}
+	export class CertificateInfo {
+	    id?: number;
+	    domain: string;
+	    port?: number;
+	    protocol?: string;
+	    issuer: string;
+	    subject: string;
+	    notBefore: string;
+	    notAfter: string;
+	    daysRemaining: number;
+	    isValid: boolean;
+	    status: string;
+	    serialNumber: string;
+	    version: number;
+	    queryTime?: string;
+	    sanDomains?: string[];
+	    sanIps?: string[];
+	    chainValid: boolean;
+	    hostnameMatch: boolean;
+	    verifyErrors?: string[];
+	    publicKeyAlgorithm?: string;
+	    publicKeySize?: number;
+	    signatureAlgorithm?: string;
+	    fingerprintSha1?: string;
+	    fingerprintSha256?: string;
+	    publicKeySha256?: string;
+	    keyUsage?: string[];
+	    extKeyUsage?: string[];
+	    basicConstraints?: string;
+	    weakFlags?: string[];
+	    chain?: ChainCertificate[];
+	    chainIssues?: string[];
+	    chainNotAfter?: string;
+	    chainDaysRemaining?: number;
+	    pem?: string;
+	    connectAddress?: string;
+	    sni?: string;
+	    connectedIp?: string;
+	    tlsVersion?: string;
+	    durationMs?: number;
+	    ipResults?: IPProbeResult[];
+	    ipInconsistent?: boolean;
+	    warnings?: string[];
+	    ocsp?: OCSPInfo;
+	    crl?: CRLCheck[];
+	    tlsScan?: TLSScanResult;
+	    ct?: CTInfo;
+	    caa?: CAAInfo;
+	    dane?: DANEInfo;
+	    http?: HTTPInfo;
+	
+	    static createFrom(source: any = {}) {
+	        return new CertificateInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.domain = source["domain"];
+	        this.port = source["port"];
+	        this.protocol = source["protocol"];
+	        this.issuer = source["issuer"];
+	        this.subject = source["subject"];
+	        this.notBefore = source["notBefore"];
+	        this.notAfter = source["notAfter"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.isValid = source["isValid"];
+	        this.status = source["status"];
+	        this.serialNumber = source["serialNumber"];
+	        this.version = source["version"];
+	        this.queryTime = source["queryTime"];
+	        this.sanDomains = source["sanDomains"];
+	        this.sanIps = source["sanIps"];
+	        this.chainValid = source["chainValid"];
+	        this.hostnameMatch = source["hostnameMatch"];
+	        this.verifyErrors = source["verifyErrors"];
+	        this.publicKeyAlgorithm = source["publicKeyAlgorithm"];
+	        this.publicKeySize = source["publicKeySize"];
+	        this.signatureAlgorithm = source["signatureAlgorithm"];
+	        this.fingerprintSha1 = source["fingerprintSha1"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.publicKeySha256 = source["publicKeySha256"];
+	        this.keyUsage = source["keyUsage"];
+	        this.extKeyUsage = source["extKeyUsage"];
+	        this.basicConstraints = source["basicConstraints"];
+	        this.weakFlags = source["weakFlags"];
+	        this.chain = this.convertValues(source["chain"], ChainCertificate);
+	        this.chainIssues = source["chainIssues"];
+	        this.chainNotAfter = source["chainNotAfter"];
+	        this.chainDaysRemaining = source["chainDaysRemaining"];
+	        this.pem = source["pem"];
+	        this.connectAddress = source["connectAddress"];
+	        this.sni = source["sni"];
+	        this.connectedIp = source["connectedIp"];
+	        this.tlsVersion = source["tlsVersion"];
+	        this.durationMs = source["durationMs"];
+	        this.ipResults = this.convertValues(source["ipResults"], IPProbeResult);
+	        this.ipInconsistent = source["ipInconsistent"];
+	        this.warnings = source["warnings"];
+	        this.ocsp = this.convertValues(source["ocsp"], OCSPInfo);
+	        this.crl = this.convertValues(source["crl"], CRLCheck);
+	        this.tlsScan = this.convertValues(source["tlsScan"], TLSScanResult);
+	        this.ct = this.convertValues(source["ct"], CTInfo);
+	        this.caa = this.convertValues(source["caa"], CAAInfo);
+	        this.dane = this.convertValues(source["dane"], DANEInfo);
+	        this.http = this.convertValues(source["http"], HTTPInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BatchQueryResult {
+	    success: boolean;
+	    message: string;
+	    total: number;
+	    results: CertificateInfo[];
+	    errors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.total = source["total"];
+	        this.results = this.convertValues(source["results"], CertificateInfo);
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class CRLCacheEntry {
+	    id: number;
+	    issuer: string;
+	    url: string;
+	    thisUpdate: string;
+	    nextUpdate: string;
+	    revokedCount: number;
+	    fetchedTime: string;
+	    stale: boolean;
+	    hoursUntilUpdate: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CRLCacheEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.issuer = source["issuer"];
+	        this.url = source["url"];
+	        this.thisUpdate = source["thisUpdate"];
+	        this.nextUpdate = source["nextUpdate"];
+	        this.revokedCount = source["revokedCount"];
+	        this.fetchedTime = source["fetchedTime"];
+	        this.stale = source["stale"];
+	        this.hoursUntilUpdate = source["hoursUntilUpdate"];
+	    }
+	}
+	
+	export class CRLStatusResult {
+	    success: boolean;
+	    message: string;
+	    total: number;
+	    stale: number;
+	    crls: CRLCacheEntry[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CRLStatusResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.total = source["total"];
+	        this.stale = source["stale"];
+	        this.crls = this.convertValues(source["crls"], CRLCacheEntry);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class CTLogListResult {
+	    success: boolean;
+	    message: string;
+	    version: string;
+	    logCount: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CTLogListResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.version = source["version"];
+	        this.logCount = source["logCount"];
+	        this.error = source["error"];
+	    }
+	}
+	export class CertEvent {
+	    id: number;
+	    watchedDomainId: number;
+	    kind: string;
+	    oldValue?: string;
+	    newValue?: string;
+	    unexpected: boolean;
+	    description: string;
+	    fingerprintSha256?: string;
+	    eventTime: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CertEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.watchedDomainId = source["watchedDomainId"];
+	        this.kind = source["kind"];
+	        this.oldValue = source["oldValue"];
+	        this.newValue = source["newValue"];
+	        this.unexpected = source["unexpected"];
+	        this.description = source["description"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.eventTime = source["eventTime"];
+	    }
+	}
+	export class CertSnapshot {
+	    serialNumber: string;
+	    issuer: string;
+	    publicKeySha256: string;
+	    sans: string[];
+	    notAfter: string;
+	    fingerprintSha256: string;
+	    firstSeenTime?: string;
+	    checkedTime?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CertSnapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.serialNumber = source["serialNumber"];
+	        this.issuer = source["issuer"];
+	        this.publicKeySha256 = source["publicKeySha256"];
+	        this.sans = source["sans"];
+	        this.notAfter = source["notAfter"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.firstSeenTime = source["firstSeenTime"];
+	        this.checkedTime = source["checkedTime"];
+	    }
+	}
+	
+	
+	
+	export class ClientCertificate {
+	    id: number;
+	    name: string;
+	    subject: string;
+	    issuer: string;
+	    notBefore: string;
+	    notAfter: string;
+	    daysRemaining: number;
+	    status: string;
+	    fingerprintSha256: string;
+	    notifyEnabled: boolean;
+	    notifyThreshold: number;
+	    usedBy: number;
+	    createdTime: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClientCertificate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.subject = source["subject"];
+	        this.issuer = source["issuer"];
+	        this.notBefore = source["notBefore"];
+	        this.notAfter = source["notAfter"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.status = source["status"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.notifyEnabled = source["notifyEnabled"];
+	        this.notifyThreshold = source["notifyThreshold"];
+	        this.usedBy = source["usedBy"];
+	        this.createdTime = source["createdTime"];
+	    }
+	}
+	export class ClientCertificateRequest {
+	    name: string;
+	    pem?: string;
+	    pkcs12?: string;
+	    password?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClientCertificateRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pem = source["pem"];
+	        this.pkcs12 = source["pkcs12"];
+	        this.password = source["password"];
+	    }
+	}
+	export class ClientCertificatesResult {
+	    success: boolean;
+	    message: string;
+	    total: number;
+	    certificates: ClientCertificate[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClientCertificatesResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.total = source["total"];
+	        this.certificates = this.convertValues(source["certificates"], ClientCertificate);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SANCoverage {
+	    name: string;
+	    type: string;
+	    covers: boolean;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SANCoverage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.covers = source["covers"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class CoverageResult {
+	    success: boolean;
+	    message: string;
+	    name: string;
+	    covered: boolean;
+	    matchedBy?: string;
+	    sans?: SANCoverage[];
+	    subject: string;
+	    serialNumber: string;
+	    fingerprintSha256: string;
+	    notAfter: string;
+	    daysRemaining: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CoverageResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.name = source["name"];
+	        this.covered = source["covered"];
+	        this.matchedBy = source["matchedBy"];
+	        this.sans = this.convertValues(source["sans"], SANCoverage);
+	        this.subject = source["subject"];
+	        this.serialNumber = source["serialNumber"];
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.notAfter = source["notAfter"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DomainTimelineResult {
+	    success: boolean;
+	    message: string;
+	    domain: string;
+	    snapshot?: CertSnapshot;
+	    events: CertEvent[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DomainTimelineResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.domain = source["domain"];
+	        this.snapshot = this.convertValues(source["snapshot"], CertSnapshot);
+	        this.events = this.convertValues(source["events"], CertEvent);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	export class HistoryPageResult {
+	    success: boolean;
+	    message: string;
+	    total: number;
+	    records: CertificateInfo[];
+	    nextCursor?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryPageResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.total = source["total"];
+	        this.records = this.convertValues(source["records"], CertificateInfo);
+	        this.nextCursor = source["nextCursor"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryQuery {
+	    domain?: string;
+	    statuses?: string[];
+	    issuer?: string;
+	    serialNumber?: string;
+	    from?: string;
+	    to?: string;
+	    keyAlgorithm?: string;
+	    signatureAlgorithm?: string;
+	    weakOnly: boolean;
+	    limit: number;
+	    cursor?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.domain = source["domain"];
+	        this.statuses = source["statuses"];
+	        this.issuer = source["issuer"];
+	        this.serialNumber = source["serialNumber"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.keyAlgorithm = source["keyAlgorithm"];
+	        this.signatureAlgorithm = source["signatureAlgorithm"];
+	        this.weakOnly = source["weakOnly"];
+	        this.limit = source["limit"];
+	        this.cursor = source["cursor"];
+	    }
+	}
+	export class HistoryQueryResult {
+	    success: boolean;
+	    message: string;
+	    total: number;
+	    records: CertificateInfo[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.total = source["total"];
+	        this.records = this.convertValues(source["records"], CertificateInfo);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ImportDomainsResult {
+	    success: boolean;
+	    message: string;
+	    total: number;
+	    successCount: number;
+	    skippedCount: number;
+	    failedCount: number;
+	    failedDomains: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportDomainsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.total = source["total"];
+	        this.successCount = source["successCount"];
+	        this.skippedCount = source["skippedCount"];
+	        this.failedCount = source["failedCount"];
+	        this.failedDomains = source["failedDomains"];
+	    }
+	}
+	export class NotificationItem {
+	    id: number;
+	    domain: string;
+	    port: number;
+	    nickname?: string;
+	    daysRemaining: number;
+	    notAfter: string;
+	    threshold: number;
+	    status: string;
+	    reason?: string;
+	    kind?: string;
+	    eventIds?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.domain = source["domain"];
+	        this.port = source["port"];
+	        this.nickname = source["nickname"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.notAfter = source["notAfter"];
+	        this.threshold = source["threshold"];
+	        this.status = source["status"];
+	        this.reason = source["reason"];
+	        this.kind = source["kind"];
+	        this.eventIds = source["eventIds"];
+	    }
+	}
+	export class NotificationResult {
+	    success: boolean;
+	    message: string;
+	    total: number;
+	    items: NotificationItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.total = source["total"];
+	        this.items = this.convertValues(source["items"], NotificationItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ProbeOptions {
+	    perIp: boolean;
+	    connectAddress?: string;
+	    sni?: string;
+	    noSni: boolean;
+	    deepScan: boolean;
+	    checkCaa: boolean;
+	    checkDane: boolean;
+	    clientCertId?: number;
+	    proxy?: string;
+	    checkHttp: boolean;
+	    warnMissingHsts: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProbeOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.perIp = source["perIp"];
+	        this.connectAddress = source["connectAddress"];
+	        this.sni = source["sni"];
+	        this.noSni = source["noSni"];
+	        this.deepScan = source["deepScan"];
+	        this.checkCaa = source["checkCaa"];
+	        this.checkDane = source["checkDane"];
+	        this.clientCertId = source["clientCertId"];
+	        this.proxy = source["proxy"];
+	        this.checkHttp = source["checkHttp"];
+	        this.warnMissingHsts = source["warnMissingHsts"];
+	    }
+	}
+	export class ProxySettingsResult {
+	    success: boolean;
+	    message: string;
+	    proxy: string;
+	    environment?: string;
+	    noProxy?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProxySettingsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.proxy = source["proxy"];
+	        this.environment = source["environment"];
+	        this.noProxy = source["noProxy"];
+	        this.error = source["error"];
+	    }
+	}
+	export class PruneReport {
+	    time: string;
+	    byAge: number;
+	    unchanged: number;
+	    overLimit: number;
+	    remaining: number;
+	    vacuumed: boolean;
+	    sizeBefore: number;
+	    sizeAfter: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PruneReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.byAge = source["byAge"];
+	        this.unchanged = source["unchanged"];
+	        this.overLimit = source["overLimit"];
+	        this.remaining = source["remaining"];
+	        this.vacuumed = source["vacuumed"];
+	        this.sizeBefore = source["sizeBefore"];
+	        this.sizeAfter = source["sizeAfter"];
+	    }
+	}
+	export class PruneResult {
+	    success: boolean;
+	    message: string;
+	    report?: PruneReport;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PruneResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.report = this.convertValues(source["report"], PruneReport);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QueryResult {
+	    success: boolean;
+	    message: string;
+	    data?: CertificateInfo;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.data = this.convertValues(source["data"], CertificateInfo);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RetentionPolicy {
+	    maxAgeDays: number;
+	    maxRowsPerTarget: number;
+	    keepOnlyChanges: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxAgeDays = source["maxAgeDays"];
+	        this.maxRowsPerTarget = source["maxRowsPerTarget"];
+	        this.keepOnlyChanges = source["keepOnlyChanges"];
+	    }
+	}
+	export class RetentionPolicyResult {
+	    success: boolean;
+	    message: string;
+	    policy: RetentionPolicy;
+	    defaults: RetentionPolicy;
+	    lastPrune?: PruneReport;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionPolicyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.policy = this.convertValues(source["policy"], RetentionPolicy);
+	        this.defaults = this.convertValues(source["defaults"], RetentionPolicy);
+	        this.lastPrune = this.convertValues(source["lastPrune"], PruneReport);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class SchemaStatus {
+	    success: boolean;
+	    message: string;
+	    version: number;
+	    latest: number;
+	    dbPath?: string;
+	    backupPath?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.version = source["version"];
+	        this.latest = source["latest"];
+	        this.dbPath = source["dbPath"];
+	        this.backupPath = source["backupPath"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Settings {
+	    connectTimeout: number;
+	    handshakeTimeout: number;
+	    retries: number;
+	    retryBackoff: number;
+	    proxy: string;
+	    dnsServer: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connectTimeout = source["connectTimeout"];
+	        this.handshakeTimeout = source["handshakeTimeout"];
+	        this.retries = source["retries"];
+	        this.retryBackoff = source["retryBackoff"];
+	        this.proxy = source["proxy"];
+	        this.dnsServer = source["dnsServer"];
+	    }
+	}
+	export class SettingsResult {
+	    success: boolean;
+	    message: string;
+	    settings: Settings;
+	    defaults: Settings;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SettingsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.settings = this.convertValues(source["settings"], Settings);
+	        this.defaults = this.convertValues(source["defaults"], Settings);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SharedCertGroup {
+	    fingerprintSha256: string;
+	    serialNumber: string;
+	    subject: string;
+	    issuer: string;
+	    notAfter: string;
+	    daysRemaining: number;
+	    status: string;
+	    wildcard: boolean;
+	    domains: string[];
+	    uncovered?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SharedCertGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fingerprintSha256 = source["fingerprintSha256"];
+	        this.serialNumber = source["serialNumber"];
+	        this.subject = source["subject"];
+	        this.issuer = source["issuer"];
+	        this.notAfter = source["notAfter"];
+	        this.daysRemaining = source["daysRemaining"];
+	        this.status = source["status"];
+	        this.wildcard = source["wildcard"];
+	        this.domains = source["domains"];
+	        this.uncovered = source["uncovered"];
+	    }
+	}
+	export class SharedCertReportResult {
+	    success: boolean;
+	    message: string;
+	    groups: SharedCertGroup[];
+	    unshared: number;
+	    failed?: string[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SharedCertReportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.groups = this.convertValues(source["groups"], SharedCertGroup);
+	        this.unshared = source["unshared"];
+	        this.failed = source["failed"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class TrustStore {
+	    id: number;
+	    name: string;
+	    isGlobal: boolean;
+	    certCount: number;
+	    subjects: string[];
+	    createdTime: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new CertificateInfo(source);
+	        return new TrustStore(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.domain = source["domain"];
-	        this.issuer = source["issuer"];
-	        this.subject = source["subject"];
-	        this.notBefore = source["notBefore"];
-	        this.notAfter = source["notAfter"];
-	        this.daysRemaining = source["daysRemaining"];
-	        this.isValid = source["isValid"];
-	        this.status = source["status"];
-	        this.serialNumber = source["serialNumber"];
-	        this.version = source["version"];
-	        this.queryTime = source["queryTime"];
-	        this.sanDomains = source["sanDomains"];
+	        this.name = source["name"];
+	        this.isGlobal = source["isGlobal"];
+	        this.certCount = source["certCount"];
+	        this.subjects = source["subjects"];
+	        this.createdTime = source["createdTime"];
 	    }
 	}
-	export class BatchQueryResult {
+	export class TrustStoresResult {
 	    success: boolean;
 	    message: string;
 	    total: number;
-	    results: CertificateInfo[];
-	    errors?: string[];
+	    stores: TrustStore[];
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new BatchQueryResult(source);
+	        return new TrustStoresResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
@@ -52,8 +1594,8 @@ export namespace main {
 	        this.success = source["success"];
 	        this.message = source["message"];
 	        this.total = source["total"];
-	        this.results = this.convertValues(source["results"], CertificateInfo);
-	        this.errors = source["errors"];
+	        this.stores = this.convertValues(source["stores"], TrustStore);
+	        this.error = source["error"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -74,16 +1616,83 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class WatchedDomain {
+	    id: number;
+	    domain: string;
+	    port: number;
+	    protocol?: string;
+	    trustStoreId: number;
+	    options: ProbeOptions;
+	    tlsGrade?: string;
+	    caaWarning?: string;
+	    hstsWarning?: string;
+	    unicodeDomain?: string;
+	    changes?: CertEvent[];
+	    nickname?: string;
+	    addedTime: string;
+	    lastCheckTime?: string;
+	    certInfo?: CertificateInfo;
+	    notifyEnabled: boolean;
+	    notifyThreshold: number;
+	    isManual: boolean;
+	    manualExpireDate?: string;
+	    manualStartDate?: string;
 	
-	export class HistoryQueryResult {
+	    static createFrom(source: any = {}) {
+	        return new WatchedDomain(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.domain = source["domain"];
+	        this.port = source["port"];
+	        this.protocol = source["protocol"];
+	        this.trustStoreId = source["trustStoreId"];
+	        this.options = this.convertValues(source["options"], ProbeOptions);
+	        this.tlsGrade = source["tlsGrade"];
+	        this.caaWarning = source["caaWarning"];
+	        this.hstsWarning = source["hstsWarning"];
+	        this.unicodeDomain = source["unicodeDomain"];
+	        this.changes = this.convertValues(source["changes"], CertEvent);
+	        this.nickname = source["nickname"];
+	        this.addedTime = source["addedTime"];
+	        this.lastCheckTime = source["lastCheckTime"];
+	        this.certInfo = this.convertValues(source["certInfo"], CertificateInfo);
+	        this.notifyEnabled = source["notifyEnabled"];
+	        this.notifyThreshold = source["notifyThreshold"];
+	        this.isManual = source["isManual"];
+	        this.manualExpireDate = source["manualExpireDate"];
+	        this.manualStartDate = source["manualStartDate"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WatchedDomainsResult {
 	    success: boolean;
 	    message: string;
 	    total: number;
-	    records: CertificateInfo[];
+	    domains: WatchedDomain[];
 	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new HistoryQueryResult(source);
+	        return new WatchedDomainsResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
@@ -91,7 +1700,7 @@ export namespace main {
 	        this.success = source["success"];
 	        this.message = source["message"];
 	        this.total = source["total"];
-	        this.records = this.convertValues(source["records"], CertificateInfo);
+	        this.domains = this.convertValues(source["domains"], WatchedDomain);
 	        this.error = source["error"];
 	    }
 	
@@ -113,70 +1722,90 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class ImportDomainsResult {
-	    success: boolean;
-	    message: string;
-	    total: number;
-	    successCount: number;
-	    skippedCount: number;
-	    failedCount: number;
-	    failedDomains: string[];
+
+}
+
+export namespace net {
+	
+	export class IPNet {
+	    IP: number[];
+	    Mask: number[];
 	
 	    static createFrom(source: any = {}) {
-	        return new ImportDomainsResult(source);
+	        return new IPNet(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.message = source["message"];
-	        this.total = source["total"];
-	        this.successCount = source["successCount"];
-	        this.skippedCount = source["skippedCount"];
-	        this.failedCount = source["failedCount"];
-	        this.failedDomains = source["failedDomains"];
+	        this.IP = source["IP"];
+	        this.Mask = source["Mask"];
 	    }
 	}
-	export class NotificationItem {
-	    id: number;
-	    domain: string;
-	    nickname?: string;
-	    daysRemaining: number;
-	    notAfter: string;
-	    threshold: number;
-	    status: string;
+
+}
+
+export namespace pkix {
+	
+	export class AttributeTypeAndValue {
+	    Type: number[];
+	    Value: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new NotificationItem(source);
+	        return new AttributeTypeAndValue(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.domain = source["domain"];
-	        this.nickname = source["nickname"];
-	        this.daysRemaining = source["daysRemaining"];
-	        this.notAfter = source["notAfter"];
-	        this.threshold = source["threshold"];
-	        this.status = source["status"];
+	        this.Type = source["Type"];
+	        this.Value = source["Value"];
 	    }
 	}
-	export class NotificationResult {
-	    success: boolean;
-	    message: string;
-	    total: number;
-	    items: NotificationItem[];
+	export class Extension {
+	    Id: number[];
+	    Critical: boolean;
+	    Value: number[];
 	
 	    static createFrom(source: any = {}) {
-	        return new NotificationResult(source);
+	        return new Extension(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.message = source["message"];
-	        this.total = source["total"];
-	        this.items = this.convertValues(source["items"], NotificationItem);
+	        this.Id = source["Id"];
+	        this.Critical = source["Critical"];
+	        this.Value = source["Value"];
+	    }
+	}
+	export class Name {
+	    Country: string[];
+	    Organization: string[];
+	    OrganizationalUnit: string[];
+	    Locality: string[];
+	    Province: string[];
+	    StreetAddress: string[];
+	    PostalCode: string[];
+	    SerialNumber: string;
+	    CommonName: string;
+	    Names: AttributeTypeAndValue[];
+	    ExtraNames: AttributeTypeAndValue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Name(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Country = source["Country"];
+	        this.Organization = source["Organization"];
+	        this.OrganizationalUnit = source["OrganizationalUnit"];
+	        this.Locality = source["Locality"];
+	        this.Province = source["Province"];
+	        this.StreetAddress = source["StreetAddress"];
+	        this.PostalCode = source["PostalCode"];
+	        this.SerialNumber = source["SerialNumber"];
+	        this.CommonName = source["CommonName"];
+	        this.Names = this.convertValues(source["Names"], AttributeTypeAndValue);
+	        this.ExtraNames = this.convertValues(source["ExtraNames"], AttributeTypeAndValue);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -197,22 +1826,31 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class QueryResult {
-	    success: boolean;
-	    message: string;
-	    data?: CertificateInfo;
-	    error?: string;
+
+}
+
+export namespace tls {
+	
+	export class Certificate {
+	    Certificate: number[][];
+	    PrivateKey: any;
+	    SupportedSignatureAlgorithms: number[];
+	    OCSPStaple: number[];
+	    SignedCertificateTimestamps: number[][];
+	    Leaf?: x509.Certificate;
 	
 	    static createFrom(source: any = {}) {
-	        return new QueryResult(source);
+	        return new Certificate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.message = source["message"];
-	        this.data = this.convertValues(source["data"], CertificateInfo);
-	        this.error = source["error"];
+	        this.Certificate = source["Certificate"];
+	        this.PrivateKey = source["PrivateKey"];
+	        this.SupportedSignatureAlgorithms = source["SupportedSignatureAlgorithms"];
+	        this.OCSPStaple = source["OCSPStaple"];
+	        this.SignedCertificateTimestamps = source["SignedCertificateTimestamps"];
+	        this.Leaf = this.convertValues(source["Leaf"], x509.Certificate);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -233,36 +1871,54 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class WatchedDomain {
-	    id: number;
-	    domain: string;
-	    nickname?: string;
-	    addedTime: string;
-	    lastCheckTime?: string;
-	    certInfo?: CertificateInfo;
-	    notifyEnabled: boolean;
-	    notifyThreshold: number;
-	    isManual: boolean;
-	    manualExpireDate?: string;
-	    manualStartDate?: string;
+
+}
+
+export namespace url {
+	
+	export class Userinfo {
+	
 	
 	    static createFrom(source: any = {}) {
-	        return new WatchedDomain(source);
+	        return new Userinfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.domain = source["domain"];
-	        this.nickname = source["nickname"];
-	        this.addedTime = source["addedTime"];
-	        this.lastCheckTime = source["lastCheckTime"];
-	        this.certInfo = this.convertValues(source["certInfo"], CertificateInfo);
-	        this.notifyEnabled = source["notifyEnabled"];
-	        this.notifyThreshold = source["notifyThreshold"];
-	        this.isManual = source["isManual"];
-	        this.manualExpireDate = source["manualExpireDate"];
-	        this.manualStartDate = source["manualStartDate"];
+	
+	    }
+	}
+	export class URL {
+	    Scheme: string;
+	    Opaque: string;
+	    // Go type: Userinfo
+	    User?: any;
+	    Host: string;
+	    Path: string;
+	    Fragment: string;
+	    RawQuery: string;
+	    RawPath: string;
+	    RawFragment: string;
+	    ForceQuery: boolean;
+	    OmitHost: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new URL(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Scheme = source["Scheme"];
+	        this.Opaque = source["Opaque"];
+	        this.User = this.convertValues(source["User"], null);
+	        this.Host = source["Host"];
+	        this.Path = source["Path"];
+	        this.Fragment = source["Fragment"];
+	        this.RawQuery = source["RawQuery"];
+	        this.RawPath = source["RawPath"];
+	        this.RawFragment = source["RawFragment"];
+	        this.ForceQuery = source["ForceQuery"];
+	        this.OmitHost = source["OmitHost"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -283,24 +1939,174 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class WatchedDomainsResult {
-	    success: boolean;
-	    message: string;
-	    total: number;
-	    domains: WatchedDomain[];
-	    error?: string;
+
+}
+
+export namespace x509 {
+	
+	export class PolicyMapping {
+	    // Go type: OID
+	    IssuerDomainPolicy: any;
+	    // Go type: OID
+	    SubjectDomainPolicy: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new WatchedDomainsResult(source);
+	        return new PolicyMapping(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.message = source["message"];
-	        this.total = source["total"];
-	        this.domains = this.convertValues(source["domains"], WatchedDomain);
-	        this.error = source["error"];
+	        this.IssuerDomainPolicy = this.convertValues(source["IssuerDomainPolicy"], null);
+	        this.SubjectDomainPolicy = this.convertValues(source["SubjectDomainPolicy"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OID {
+	
+	
+	    static createFrom(source: any = {}) {
+	        return new OID(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	
+	    }
+	}
+	export class Certificate {
+	    Raw: number[];
+	    RawTBSCertificate: number[];
+	    RawSubjectPublicKeyInfo: number[];
+	    RawSubject: number[];
+	    RawIssuer: number[];
+	    RawSignatureAlgorithm: number[];
+	    Signature: number[];
+	    SignatureAlgorithm: number;
+	    PublicKeyAlgorithm: number;
+	    PublicKey: any;
+	    Version: number;
+	    // Go type: big
+	    SerialNumber?: any;
+	    Issuer: pkix.Name;
+	    Subject: pkix.Name;
+	    // Go type: time
+	    NotBefore: any;
+	    // Go type: time
+	    NotAfter: any;
+	    KeyUsage: number;
+	    Extensions: pkix.Extension[];
+	    ExtraExtensions: pkix.Extension[];
+	    UnhandledCriticalExtensions: number[][];
+	    ExtKeyUsage: number[];
+	    UnknownExtKeyUsage: number[][];
+	    BasicConstraintsValid: boolean;
+	    IsCA: boolean;
+	    MaxPathLen: number;
+	    MaxPathLenZero: boolean;
+	    SubjectKeyId: number[];
+	    AuthorityKeyId: number[];
+	    OCSPServer: string[];
+	    IssuingCertificateURL: string[];
+	    DNSNames: string[];
+	    EmailAddresses: string[];
+	    IPAddresses: number[][];
+	    URIs: url.URL[];
+	    PermittedDNSDomainsCritical: boolean;
+	    PermittedDNSDomains: string[];
+	    ExcludedDNSDomains: string[];
+	    PermittedIPRanges: net.IPNet[];
+	    ExcludedIPRanges: net.IPNet[];
+	    PermittedEmailAddresses: string[];
+	    ExcludedEmailAddresses: string[];
+	    PermittedURIDomains: string[];
+	    ExcludedURIDomains: string[];
+	    CRLDistributionPoints: string[];
+	    PolicyIdentifiers: number[][];
+	    Policies: OID[];
+	    InhibitAnyPolicy: number;
+	    InhibitAnyPolicyZero: boolean;
+	    InhibitPolicyMapping: number;
+	    InhibitPolicyMappingZero: boolean;
+	    RequireExplicitPolicy: number;
+	    RequireExplicitPolicyZero: boolean;
+	    PolicyMappings: PolicyMapping[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Certificate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Raw = source["Raw"];
+	        this.RawTBSCertificate = source["RawTBSCertificate"];
+	        this.RawSubjectPublicKeyInfo = source["RawSubjectPublicKeyInfo"];
+	        this.RawSubject = source["RawSubject"];
+	        this.RawIssuer = source["RawIssuer"];
+	        this.RawSignatureAlgorithm = source["RawSignatureAlgorithm"];
+	        this.Signature = source["Signature"];
+	        this.SignatureAlgorithm = source["SignatureAlgorithm"];
+	        this.PublicKeyAlgorithm = source["PublicKeyAlgorithm"];
+	        this.PublicKey = source["PublicKey"];
+	        this.Version = source["Version"];
+	        this.SerialNumber = this.convertValues(source["SerialNumber"], null);
+	        this.Issuer = this.convertValues(source["Issuer"], pkix.Name);
+	        this.Subject = this.convertValues(source["Subject"], pkix.Name);
+	        this.NotBefore = this.convertValues(source["NotBefore"], null);
+	        this.NotAfter = this.convertValues(source["NotAfter"], null);
+	        this.KeyUsage = source["KeyUsage"];
+	        this.Extensions = this.convertValues(source["Extensions"], pkix.Extension);
+	        this.ExtraExtensions = this.convertValues(source["ExtraExtensions"], pkix.Extension);
+	        this.UnhandledCriticalExtensions = source["UnhandledCriticalExtensions"];
+	        this.ExtKeyUsage = source["ExtKeyUsage"];
+	        this.UnknownExtKeyUsage = source["UnknownExtKeyUsage"];
+	        this.BasicConstraintsValid = source["BasicConstraintsValid"];
+	        this.IsCA = source["IsCA"];
+	        this.MaxPathLen = source["MaxPathLen"];
+	        this.MaxPathLenZero = source["MaxPathLenZero"];
+	        this.SubjectKeyId = source["SubjectKeyId"];
+	        this.AuthorityKeyId = source["AuthorityKeyId"];
+	        this.OCSPServer = source["OCSPServer"];
+	        this.IssuingCertificateURL = source["IssuingCertificateURL"];
+	        this.DNSNames = source["DNSNames"];
+	        this.EmailAddresses = source["EmailAddresses"];
+	        this.IPAddresses = source["IPAddresses"];
+	        this.URIs = this.convertValues(source["URIs"], url.URL);
+	        this.PermittedDNSDomainsCritical = source["PermittedDNSDomainsCritical"];
+	        this.PermittedDNSDomains = source["PermittedDNSDomains"];
+	        this.ExcludedDNSDomains = source["ExcludedDNSDomains"];
+	        this.PermittedIPRanges = this.convertValues(source["PermittedIPRanges"], net.IPNet);
+	        this.ExcludedIPRanges = this.convertValues(source["ExcludedIPRanges"], net.IPNet);
+	        this.PermittedEmailAddresses = source["PermittedEmailAddresses"];
+	        this.ExcludedEmailAddresses = source["ExcludedEmailAddresses"];
+	        this.PermittedURIDomains = source["PermittedURIDomains"];
+	        this.ExcludedURIDomains = source["ExcludedURIDomains"];
+	        this.CRLDistributionPoints = source["CRLDistributionPoints"];
+	        this.PolicyIdentifiers = source["PolicyIdentifiers"];
+	        this.Policies = this.convertValues(source["Policies"], OID);
+	        this.InhibitAnyPolicy = source["InhibitAnyPolicy"];
+	        this.InhibitAnyPolicyZero = source["InhibitAnyPolicyZero"];
+	        this.InhibitPolicyMapping = source["InhibitPolicyMapping"];
+	        this.InhibitPolicyMappingZero = source["InhibitPolicyMappingZero"];
+	        this.RequireExplicitPolicy = source["RequireExplicitPolicy"];
+	        this.RequireExplicitPolicyZero = source["RequireExplicitPolicyZero"];
+	        this.PolicyMappings = this.convertValues(source["PolicyMappings"], PolicyMapping);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
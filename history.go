@@ -0,0 +1,364 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 历史记录分页大小
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 500
+)
+
+// HistoryQuery 历史记录查询条件，各条件同时满足；结果按查询时间倒序分页
+type HistoryQuery struct {
+	Domain             string   `json:"domain,omitempty"`             // 域名包含的文字（不区分大小写）
+	Statuses           []string `json:"statuses,omitempty"`           // 状态，满足任意一个即可
+	Issuer             string   `json:"issuer,omitempty"`             // 颁发者包含的文字
+	SerialNumber       string   `json:"serialNumber,omitempty"`       // 序列号（完全匹配）
+	From               string   `json:"from,omitempty"`               // 查询时间起（YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS）
+	To                 string   `json:"to,omitempty"`                 // 查询时间止（仅日期时包含当天）
+	KeyAlgorithm       string   `json:"keyAlgorithm,omitempty"`       // 公钥算法，如 "RSA"、"ECDSA"
+	SignatureAlgorithm string   `json:"signatureAlgorithm,omitempty"` // 签名算法，如 "SHA1-RSA"
+	WeakOnly           bool     `json:"weakOnly"`                     // 仅返回存在弱配置的证书
+	Limit              int      `json:"limit"`                        // 每页条数（默认50，最多500）
+	Cursor             string   `json:"cursor,omitempty"`             // 上一页返回的 nextCursor，为空时从最新的记录开始
+}
+
+// HistoryPageResult 历史记录分页查询结果
+type HistoryPageResult struct {
+	Success    bool              `json:"success"`
+	Message    string            `json:"message"`
+	Total      int               `json:"total"` // 满足条件的记录总数（不受分页影响）
+	Records    []CertificateInfo `json:"records"`
+	NextCursor string            `json:"nextCursor,omitempty"` // 下一页的游标，为空表示没有更多记录
+	Error      string            `json:"error,omitempty"`
+}
+
+// historyDetails 历史记录中以JSON保存的检查结果（没有对应的独立列）
+type historyDetails struct {
+	ChainIssues    []string        `json:"chainIssues,omitempty"`
+	IPResults      []IPProbeResult `json:"ipResults,omitempty"`
+	IPInconsistent bool            `json:"ipInconsistent,omitempty"`
+	Warnings       []string        `json:"warnings,omitempty"`
+	OCSP           *OCSPInfo       `json:"ocsp,omitempty"`
+	CRL            []CRLCheck      `json:"crl,omitempty"`
+	CT             *CTInfo         `json:"ct,omitempty"`
+	CAA            *CAAInfo        `json:"caa,omitempty"`
+	DANE           *DANEInfo       `json:"dane,omitempty"`
+	HTTP           *HTTPInfo       `json:"http,omitempty"`
+}
+
+// newHistoryDetails 提取证书信息中需要以JSON保存的检查结果
+func newHistoryDetails(cert *CertificateInfo) historyDetails {
+	return historyDetails{
+		ChainIssues:    cert.ChainIssues,
+		IPResults:      cert.IPResults,
+		IPInconsistent: cert.IPInconsistent,
+		Warnings:       cert.Warnings,
+		OCSP:           cert.OCSP,
+		CRL:            cert.CRL,
+		CT:             cert.CT,
+		CAA:            cert.CAA,
+		DANE:           cert.DANE,
+		HTTP:           cert.HTTP,
+	}
+}
+
+// apply 将检查结果写回证书信息
+func (d historyDetails) apply(cert *CertificateInfo) {
+	cert.ChainIssues = d.ChainIssues
+	cert.IPResults = d.IPResults
+	cert.IPInconsistent = d.IPInconsistent
+	cert.Warnings = d.Warnings
+	cert.OCSP = d.OCSP
+	cert.CRL = d.CRL
+	cert.CT = d.CT
+	cert.CAA = d.CAA
+	cert.DANE = d.DANE
+	cert.HTTP = d.HTTP
+}
+
+// GetHistoryRecord 获取单条历史记录的完整信息：重新解析保存的证书链（证书链详情、SAN等），
+// 并还原查询时的检查结果；状态、校验结果和剩余天数保持查询时的值
+func (a *App) GetHistoryRecord(id int64) QueryResult {
+	if a.db == nil {
+		return QueryResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	var pemText, details sql.NullString
+	row := a.db.QueryRow("SELECT "+historyColumns+", pem, details FROM certificates WHERE id = ?", id)
+	cert, err := scanHistoryRecord(row, &pemText, &details)
+	if err == sql.ErrNoRows {
+		return QueryResult{
+			Success: false,
+			Error:   "历史记录不存在",
+			Message: "历史记录不存在",
+		}
+	}
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+
+	hasDetails := details.Valid && details.String != ""
+	if hasDetails {
+		var d historyDetails
+		if json.Unmarshal([]byte(details.String), &d) == nil {
+			d.apply(cert)
+		}
+	}
+
+	// 早期的记录没有保存证书原文
+	if !pemText.Valid || pemText.String == "" {
+		return QueryResult{
+			Success: true,
+			Message: "该记录查询时未保存证书原文，仅包含基本信息",
+			Data:    cert,
+		}
+	}
+
+	certs, err := parseCAPEM(pemText.String)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("解析保存的证书失败：%v", err),
+		}
+	}
+
+	// 以查询时间为准重新分析证书链，得到查询当时的链中最早过期时间
+	queryTime, err := time.ParseInLocation("2006-01-02 15:04:05", cert.QueryTime, time.Local)
+	if err != nil {
+		queryTime = time.Now()
+	}
+	// 证书链问题（如缺少中间证书）与信任根有关，使用该目标当前的信任根设置
+	opts, err := a.historyProbeOptions(cert.Domain, cert.Port)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: err.Error(),
+		}
+	}
+	parsed := buildCertificateInfo(Target{Host: cert.Domain, Port: cert.Port, Protocol: cert.Protocol}, certs, opts, queryTime)
+	cert.PEM = pemText.String
+	cert.Chain = parsed.Chain
+	cert.ChainNotAfter = parsed.ChainNotAfter
+	cert.ChainDaysRemaining = parsed.ChainDaysRemaining
+	if !hasDetails {
+		// 检查结果中保存了查询时的证书链问题（没有问题时为空），只有未保存时才重新分析
+		cert.ChainIssues = parsed.ChainIssues
+	}
+	if len(cert.SANDomains) == 0 && len(cert.SANIPs) == 0 {
+		cert.SANDomains = parsed.SANDomains
+		cert.SANIPs = parsed.SANIPs
+	}
+
+	return QueryResult{
+		Success: true,
+		Message: "查询成功",
+		Data:    cert,
+	}
+}
+
+// historyProbeOptions 返回重新分析历史记录的证书链使用的选项：目标仍在关注列表中时使用其信任根，否则使用全局信任根
+func (a *App) historyProbeOptions(host string, port int) (ProbeOptions, error) {
+	var trustStoreID int64
+	wd, err := a.findWatchedDomain(host, port)
+	if err != nil {
+		return ProbeOptions{}, err
+	}
+	if wd != nil {
+		trustStoreID = wd.TrustStoreID
+	}
+	roots, err := a.trustRoots(trustStoreID)
+	if err != nil {
+		return ProbeOptions{}, err
+	}
+	return ProbeOptions{Roots: roots}, nil
+}
+
+// QueryHistory 按条件分页查询历史记录，使用游标（上一页最后一条记录的查询时间和ID）翻页，
+// 翻页期间新增的记录不会导致重复或遗漏
+func (a *App) QueryHistory(query HistoryQuery) HistoryPageResult {
+	if a.db == nil {
+		return HistoryPageResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	conditions, args, err := query.conditions()
+	if err != nil {
+		return HistoryPageResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: err.Error(),
+		}
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := a.db.QueryRow("SELECT COUNT(*) FROM certificates "+where, args...).Scan(&total); err != nil {
+		return HistoryPageResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+
+	if query.Cursor != "" {
+		cursorTime, cursorID, err := parseHistoryCursor(query.Cursor)
+		if err != nil {
+			return HistoryPageResult{
+				Success: false,
+				Error:   err.Error(),
+				Message: err.Error(),
+			}
+		}
+		conditions = append(conditions, "(query_time < ? OR (query_time = ? AND id < ?))")
+		args = append(args, cursorTime, cursorTime, cursorID)
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultHistoryPageSize
+	}
+	if limit > maxHistoryPageSize {
+		limit = maxHistoryPageSize
+	}
+	// 多取一条用于判断是否还有下一页
+	args = append(args, limit+1)
+
+	rows, err := a.db.Query("SELECT "+historyColumns+" FROM certificates "+where+
+		" ORDER BY query_time DESC, id DESC LIMIT ?", args...)
+	if err != nil {
+		return HistoryPageResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	records := []CertificateInfo{}
+	nextCursor := ""
+	for rows.Next() {
+		if len(records) == limit {
+			last := records[len(records)-1]
+			nextCursor = formatHistoryCursor(last.QueryTime, last.ID)
+			break
+		}
+		cert, err := scanHistoryRecord(rows)
+		if err != nil {
+			return HistoryPageResult{
+				Success: false,
+				Error:   fmt.Sprintf("读取历史记录失败: %v", err),
+			}
+		}
+		records = append(records, *cert)
+	}
+	if err := rows.Err(); err != nil {
+		return HistoryPageResult{
+			Success: false,
+			Error:   fmt.Sprintf("读取历史记录失败: %v", err),
+		}
+	}
+
+	return HistoryPageResult{
+		Success:    true,
+		Message:    fmt.Sprintf("共 %d 条记录，本页 %d 条", total, len(records)),
+		Total:      total,
+		Records:    records,
+		NextCursor: nextCursor,
+	}
+}
+
+// conditions 将查询条件转换为SQL条件和参数（不含游标）
+func (q HistoryQuery) conditions() ([]string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+	if domain := strings.ToLower(strings.TrimSpace(q.Domain)); domain != "" {
+		conditions = append(conditions, "instr(lower(domain), ?) > 0")
+		args = append(args, domain)
+	}
+	if len(q.Statuses) > 0 {
+		placeholders := make([]string, len(q.Statuses))
+		for i, status := range q.Statuses {
+			placeholders[i] = "?"
+			args = append(args, status)
+		}
+		conditions = append(conditions, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if issuer := strings.ToLower(strings.TrimSpace(q.Issuer)); issuer != "" {
+		conditions = append(conditions, "instr(lower(issuer), ?) > 0")
+		args = append(args, issuer)
+	}
+	if serial := strings.TrimSpace(q.SerialNumber); serial != "" {
+		conditions = append(conditions, "serial_number = ?")
+		args = append(args, serial)
+	}
+
+	from, err := parseDateTime(strings.TrimSpace(q.From))
+	if err != nil {
+		return nil, nil, fmt.Errorf("开始时间：%v", err)
+	}
+	if from != "" {
+		conditions = append(conditions, "query_time >= ?")
+		args = append(args, from)
+	}
+	to := strings.TrimSpace(q.To)
+	if _, err := time.Parse("2006-01-02", to); err == nil {
+		// 仅日期时包含当天
+		to += " 23:59:59"
+	}
+	to, err = parseDateTime(to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("结束时间：%v", err)
+	}
+	if to != "" {
+		conditions = append(conditions, "query_time <= ?")
+		args = append(args, to)
+	}
+
+	if q.KeyAlgorithm != "" {
+		conditions = append(conditions, "key_algorithm = ?")
+		args = append(args, q.KeyAlgorithm)
+	}
+	if q.SignatureAlgorithm != "" {
+		conditions = append(conditions, "signature_algorithm = ?")
+		args = append(args, q.SignatureAlgorithm)
+	}
+	if q.WeakOnly {
+		conditions = append(conditions, "is_weak = 1")
+	}
+	return conditions, args, nil
+}
+
+// formatHistoryCursor 生成分页游标（查询时间和ID）
+func formatHistoryCursor(queryTime string, id int64) string {
+	return queryTime + "|" + strconv.FormatInt(id, 10)
+}
+
+// parseHistoryCursor 解析分页游标
+func parseHistoryCursor(cursor string) (string, int64, error) {
+	queryTime, idText, ok := strings.Cut(cursor, "|")
+	id, err := strconv.ParseInt(idText, 10, 64)
+	if !ok || err != nil {
+		return "", 0, fmt.Errorf("无效的分页游标: %s", cursor)
+	}
+	return queryTime, id, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// insertHistory 插入一条指定查询时间的历史记录
+func insertHistory(t *testing.T, a *App, domain, queryTime string) {
+	t.Helper()
+	_, err := a.db.Exec(`
+	INSERT INTO certificates (domain, issuer, subject, not_before, not_after, days_remaining, is_valid, status, serial_number, version, query_time)
+	VALUES (?, 'Test CA', ?, '2025-01-01 00:00:00', '2026-01-01 00:00:00', 30, 1, 'valid', '01', 3, ?)`,
+		domain, domain, queryTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueryHistoryPagination(t *testing.T) {
+	a := newTestApp(t)
+	for i := 0; i < 7; i++ {
+		// 两条记录共用一个查询时间，翻页时按ID区分
+		insertHistory(t, a, fmt.Sprintf("host%d.example.com", i), fmt.Sprintf("2025-06-01 10:00:%02d", i/2))
+	}
+
+	seen := make(map[int64]bool)
+	cursor := ""
+	pages := 0
+	for {
+		result := a.QueryHistory(HistoryQuery{Limit: 3, Cursor: cursor})
+		if !result.Success {
+			t.Fatalf("查询失败: %s", result.Error)
+		}
+		if result.Total != 7 {
+			t.Fatalf("总数 %d，期望 7", result.Total)
+		}
+		for _, record := range result.Records {
+			if seen[record.ID] {
+				t.Fatalf("记录 %d 重复出现", record.ID)
+			}
+			seen[record.ID] = true
+		}
+		pages++
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	if len(seen) != 7 || pages != 3 {
+		t.Fatalf("共 %d 页 %d 条记录，期望 3 页 7 条", pages, len(seen))
+	}
+
+	filtered := a.QueryHistory(HistoryQuery{Domain: "HOST3", From: "2025-06-01", To: "2025-06-01"})
+	if !filtered.Success || filtered.Total != 1 || len(filtered.Records) != 1 {
+		t.Fatalf("按域名筛选结果不正确: %+v", filtered)
+	}
+
+	if _, err := a.db.Exec("UPDATE certificates SET key_algorithm = 'RSA', is_weak = 1 WHERE domain = 'host5.example.com'"); err != nil {
+		t.Fatal(err)
+	}
+	weak := a.QueryHistory(HistoryQuery{KeyAlgorithm: "RSA", WeakOnly: true})
+	if !weak.Success || weak.Total != 1 || weak.Records[0].Domain != "host5.example.com" {
+		t.Fatalf("按弱配置筛选结果不正确: %+v", weak)
+	}
+
+	latest := a.GetHistory(2)
+	if !latest.Success || len(latest.Records) != 2 || latest.Records[0].Domain != "host6.example.com" || latest.Records[1].Domain != "host5.example.com" {
+		t.Fatalf("GetHistory 应按查询时间和ID倒序返回最近的记录: %+v", latest.Records)
+	}
+}
+
+func TestQueryHistoryScanError(t *testing.T) {
+	a := newTestApp(t)
+	insertHistory(t, a, "good.example.com", "2025-06-01 10:00:00")
+	insertHistory(t, a, "bad.example.com", "2025-06-01 11:00:00")
+	if _, err := a.db.Exec("UPDATE certificates SET days_remaining = 'x' WHERE domain = 'bad.example.com'"); err != nil {
+		t.Fatal(err)
+	}
+
+	result := a.QueryHistory(HistoryQuery{})
+	if result.Success {
+		t.Fatalf("无法读取的记录应返回错误，得到 %d 条记录", len(result.Records))
+	}
+}
+
+func TestGetHistoryRecordTrustStore(t *testing.T) {
+	a := newTestApp(t)
+	root := newTestCert(t, "Private Root", nil)
+	intermediate := newTestCert(t, "Private Intermediate", root)
+	leaf := newTestCert(t, "example.com", intermediate, "example.com")
+	var chain []byte
+	for _, c := range []*testCert{leaf, intermediate} {
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})...)
+	}
+	rootPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.cert.Raw}))
+
+	// 早期记录没有保存检查结果，按目标的信任根重新分析证书链
+	insertHistory(t, a, "example.com", time.Now().Format("2006-01-02 15:04:05"))
+	if _, err := a.db.Exec("UPDATE certificates SET pem = ?", string(chain)); err != nil {
+		t.Fatal(err)
+	}
+	missingIntermediate := func() bool {
+		result := a.GetHistoryRecord(1)
+		if !result.Success {
+			t.Fatalf("查询失败: %s", result.Error)
+		}
+		return strings.Contains(strings.Join(result.Data.ChainIssues, "\n"), "缺少中间证书")
+	}
+	if !missingIntermediate() {
+		t.Fatal("仅使用系统根证书时应报告缺少中间证书")
+	}
+
+	store, err := a.db.Exec("INSERT INTO trust_stores (name, pem) VALUES ('private', ?)", rootPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeID, _ := store.LastInsertId()
+	if _, err := a.db.Exec("INSERT INTO watched_domains (domain, port, trust_store_id) VALUES ('example.com', 443, ?)", storeID); err != nil {
+		t.Fatal(err)
+	}
+	if missingIntermediate() {
+		t.Fatal("应使用关注域名指定的信任根分析证书链")
+	}
+
+	// 保存了检查结果的记录显示查询时的证书链问题
+	if _, err := a.db.Exec("UPDATE watched_domains SET trust_store_id = 0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.db.Exec("UPDATE certificates SET details = '{}'"); err != nil {
+		t.Fatal(err)
+	}
+	if missingIntermediate() {
+		t.Fatal("应显示查询时保存的证书链问题")
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpMaxRedirects 跟随重定向的最大次数
+const httpMaxRedirects = 10
+
+// hstsPreloadMaxAge 加入HSTS预加载列表要求的最小 max-age（1年）
+const hstsPreloadMaxAge = 31536000
+
+// HSTSInfo Strict-Transport-Security 响应头
+type HSTSInfo struct {
+	Header            string `json:"header"`
+	MaxAge            int64  `json:"maxAge"` // 秒
+	IncludeSubDomains bool   `json:"includeSubDomains"`
+	Preload           bool   `json:"preload"`
+	PreloadReady      bool   `json:"preloadReady"` // 满足预加载列表要求：max-age≥1年、includeSubDomains、preload
+}
+
+// HTTPRedirectInfo 通过80端口以HTTP访问时的响应
+type HTTPRedirectInfo struct {
+	StatusCode int    `json:"statusCode,omitempty"`
+	Location   string `json:"location,omitempty"` // 重定向地址
+	ToHTTPS    bool   `json:"toHttps"`            // 是否重定向到HTTPS
+	Error      string `json:"error,omitempty"`
+}
+
+// HTTPEndpointCert 重定向最终到达其他主机时，该主机的证书
+type HTTPEndpointCert struct {
+	Host              string   `json:"host"`
+	Subject           string   `json:"subject"`
+	Issuer            string   `json:"issuer"`
+	NotAfter          string   `json:"notAfter"`
+	DaysRemaining     int      `json:"daysRemaining"`
+	FingerprintSHA256 string   `json:"fingerprintSha256"`
+	ChainValid        bool     `json:"chainValid"`
+	HostnameMatch     bool     `json:"hostnameMatch"`
+	VerifyErrors      []string `json:"verifyErrors,omitempty"`
+}
+
+// HTTPInfo HTTP层检查结果：HSTS、80端口重定向、重定向最终目标及其证书
+type HTTPInfo struct {
+	URL          string            `json:"url"`                    // 请求的地址
+	StatusCode   int               `json:"statusCode,omitempty"`   // 最终响应的状态码
+	Redirects    []string          `json:"redirects,omitempty"`    // 依次跳转到的地址
+	FinalURL     string            `json:"finalUrl,omitempty"`     // 重定向最终到达的地址
+	FinalCert    *HTTPEndpointCert `json:"finalCert,omitempty"`    // 最终地址与探测目标不同时的证书
+	HSTS         *HSTSInfo         `json:"hsts,omitempty"`         // 探测目标返回的HSTS头，nil表示未设置
+	HTTPRedirect *HTTPRedirectInfo `json:"httpRedirect,omitempty"` // 80端口的行为（仅探测443端口时检查）
+	Error        string            `json:"error,omitempty"`        // 请求失败原因
+}
+
+// missingHSTSWarning 返回未设置HSTS时的警告，请求失败时为空
+func (h *HTTPInfo) missingHSTSWarning() string {
+	if h == nil || h.Error != "" {
+		return ""
+	}
+	if h.HSTS == nil {
+		return "未设置HSTS（Strict-Transport-Security）响应头"
+	}
+	if h.HSTS.MaxAge <= 0 {
+		return "HSTS的 max-age 为0，浏览器将清除HSTS策略"
+	}
+	return ""
+}
+
+// checkHTTP 握手后发送HTTP GET请求，记录HSTS响应头、重定向链及最终目标的证书，并检查80端口是否跳转到HTTPS
+func (a *App) checkHTTP(ctx context.Context, info *CertificateInfo, target Target, opts ProbeOptions) {
+	result := &HTTPInfo{URL: (&url.URL{Scheme: "https", Host: urlHost(target.Host, target.Port, 443), Path: "/"}).String()}
+	info.HTTP = result
+
+	// 探测目标的第一个响应（HSTS只对该响应有效）
+	var first *http.Response
+	client := &http.Client{
+		Transport: a.httpTransport(target, opts),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) == 1 {
+				first = req.Response
+			}
+			if len(via) > httpMaxRedirects {
+				return fmt.Errorf("重定向超过 %d 次", httpMaxRedirects)
+			}
+			result.Redirects = append(result.Redirects, req.URL.String())
+			return nil
+		},
+	}
+
+	resp, err := httpGet(ctx, client, result.URL)
+	if err != nil {
+		result.Error = probeError(ctx, err).Error()
+		return
+	}
+	resp.Body.Close()
+	if first == nil {
+		first = resp
+	}
+
+	result.StatusCode = resp.StatusCode
+	result.FinalURL = resp.Request.URL.String()
+	result.HSTS = parseHSTS(first.Header.Get("Strict-Transport-Security"))
+
+	// 最终到达其他主机或端口时记录其证书
+	final := resp.Request.URL
+	if final.Scheme == "http" {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("HTTPS请求最终被重定向到未加密的地址 %s", result.FinalURL))
+	} else if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 && !sameEndpoint(final, target) {
+		result.FinalCert = endpointCertificate(final.Hostname(), resp.TLS.PeerCertificates, opts.Roots, time.Now())
+		if len(result.FinalCert.VerifyErrors) > 0 || result.FinalCert.DaysRemaining <= 0 {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("重定向目标 %s 的证书无效", final.Host))
+		}
+	}
+
+	if opts.WarnMissingHSTS {
+		if warning := result.missingHSTSWarning(); warning != "" {
+			info.Warnings = append(info.Warnings, warning)
+		}
+	}
+
+	// 标准HTTPS端口的站点检查80端口是否跳转到HTTPS
+	if target.Port == 443 {
+		result.HTTPRedirect = a.checkHTTPRedirect(ctx, target, opts)
+	}
+}
+
+// checkHTTPRedirect 以HTTP访问80端口，记录响应（不跟随重定向）
+func (a *App) checkHTTPRedirect(ctx context.Context, target Target, opts ProbeOptions) *HTTPRedirectInfo {
+	result := &HTTPRedirectInfo{}
+	client := &http.Client{
+		Transport: a.httpTransport(target, opts),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := httpGet(ctx, client, (&url.URL{Scheme: "http", Host: urlHost(target.Host, 80, 80), Path: "/"}).String())
+	if err != nil {
+		result.Error = probeError(ctx, err).Error()
+		return result
+	}
+	resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	if location, err := resp.Location(); err == nil {
+		result.Location = location.String()
+		result.ToHTTPS = location.Scheme == "https"
+	}
+	return result
+}
+
+// httpTransport 返回HTTP检查使用的连接方式：探测目标按连接地址拨号，经由代理时与握手相同，不校验证书（证书单独校验）
+func (a *App) httpTransport(target Target, opts ProbeOptions) *http.Transport {
+	settings := a.currentSettings()
+	config := &tls.Config{InsecureSkipVerify: true}
+	if opts.ClientCert != nil {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return opts.ClientCert, nil
+		}
+	}
+
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialCtx, cancel := context.WithTimeout(ctx, settings.connectTimeout())
+			defer cancel()
+			conn, _, err := a.dialTarget(dialCtx, httpDialAddress(address, target, opts), opts)
+			return conn, err
+		},
+		TLSClientConfig:       config,
+		TLSHandshakeTimeout:   settings.handshakeTimeout(),
+		ResponseHeaderTimeout: settings.handshakeTimeout(),
+		DisableKeepAlives:     true,
+	}
+}
+
+// httpDialAddress 探测目标指定了连接地址时，访问目标主机的请求（包括80端口）改为连接该地址
+func httpDialAddress(address string, target Target, opts ProbeOptions) string {
+	if address == target.Address() {
+		return opts.dialAddress(target)
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || opts.ConnectAddress == "" || !strings.EqualFold(host, target.Host) {
+		return address
+	}
+	connectHost, _, err := net.SplitHostPort(opts.dialAddress(target))
+	if err != nil {
+		return address
+	}
+	return net.JoinHostPort(connectHost, port)
+}
+
+// httpGet 发送GET请求
+func httpGet(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "SSL-Cert-Checker")
+	return client.Do(req)
+}
+
+// parseHSTS 解析 Strict-Transport-Security 响应头，为空时返回nil
+func parseHSTS(header string) *HSTSInfo {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return nil
+	}
+
+	hsts := &HSTSInfo{Header: header}
+	for _, directive := range strings.Split(header, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "max-age":
+			if maxAge, err := strconv.ParseInt(strings.Trim(strings.TrimSpace(value), `"`), 10, 64); err == nil {
+				hsts.MaxAge = maxAge
+			}
+		case "includesubdomains":
+			hsts.IncludeSubDomains = true
+		case "preload":
+			hsts.Preload = true
+		}
+	}
+	hsts.PreloadReady = hsts.MaxAge >= hstsPreloadMaxAge && hsts.IncludeSubDomains && hsts.Preload
+	return hsts
+}
+
+// endpointCertificate 汇总重定向目标的证书及校验结果
+func endpointCertificate(host string, certs []*x509.Certificate, roots *x509.CertPool, now time.Time) *HTTPEndpointCert {
+	leaf := certs[0]
+	verified := verifyCertificate(certs, host, roots, now)
+	return &HTTPEndpointCert{
+		Host:              host,
+		Subject:           leaf.Subject.CommonName,
+		Issuer:            leaf.Issuer.CommonName,
+		NotAfter:          leaf.NotAfter.Format("2006-01-02 15:04:05"),
+		DaysRemaining:     int(leaf.NotAfter.Sub(now).Hours() / 24),
+		FingerprintSHA256: fingerprintSHA256(leaf),
+		ChainValid:        verified.ChainValid,
+		HostnameMatch:     verified.HostnameMatch,
+		VerifyErrors:      verified.Errors,
+	}
+}
+
+// sameEndpoint 判断URL是否指向探测目标本身
+func sameEndpoint(u *url.URL, target Target) bool {
+	port := 443
+	if p, err := strconv.Atoi(u.Port()); err == nil {
+		port = p
+	}
+	return strings.EqualFold(u.Hostname(), target.Host) && port == target.Port
+}
+
+// urlHost 返回URL中的主机部分，默认端口时省略端口
+func urlHost(host string, port, defaultPort int) string {
+	if port == defaultPort {
+		if strings.Contains(host, ":") {
+			return "[" + host + "]"
+		}
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestParseHSTS(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *HSTSInfo
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"max-age=31536000", &HSTSInfo{MaxAge: 31536000}},
+		{"max-age=63072000; includeSubDomains; preload", &HSTSInfo{MaxAge: 63072000, IncludeSubDomains: true, Preload: true, PreloadReady: true}},
+		{`Max-Age="31536000" ; INCLUDESUBDOMAINS ; Preload`, &HSTSInfo{MaxAge: 31536000, IncludeSubDomains: true, Preload: true, PreloadReady: true}},
+		{"max-age=86400; includeSubDomains; preload", &HSTSInfo{MaxAge: 86400, IncludeSubDomains: true, Preload: true}},
+		{"max-age=31536000; preload", &HSTSInfo{MaxAge: 31536000, Preload: true}},
+		{"max-age=0", &HSTSInfo{}},
+		{"max-age=abc; includeSubDomains", &HSTSInfo{IncludeSubDomains: true}},
+		{"includeSubDomains;;unknown=1", &HSTSInfo{IncludeSubDomains: true}},
+	}
+	for _, tt := range tests {
+		got := parseHSTS(tt.header)
+		if tt.want == nil {
+			if got != nil {
+				t.Errorf("parseHSTS(%q) = %+v，期望 nil", tt.header, got)
+			}
+			continue
+		}
+		tt.want.Header = tt.header
+		if got == nil || *got != *tt.want {
+			t.Errorf("parseHSTS(%q) = %+v，期望 %+v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMissingHSTSWarning(t *testing.T) {
+	tests := []struct {
+		name string
+		info *HTTPInfo
+		want bool
+	}{
+		{"未检查", nil, false},
+		{"请求失败", &HTTPInfo{Error: "连接被拒绝"}, false},
+		{"未设置", &HTTPInfo{}, true},
+		{"max-age为0", &HTTPInfo{HSTS: parseHSTS("max-age=0")}, true},
+		{"已设置", &HTTPInfo{HSTS: parseHSTS("max-age=300")}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.info.missingHSTSWarning(); (got != "") != tt.want {
+			t.Errorf("%s: missingHSTSWarning() = %q", tt.name, got)
+		}
+	}
+}
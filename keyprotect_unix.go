@@ -0,0 +1,16 @@
+//go:build !windows
+
+package main
+
+// keyProtection 密钥文件的保护方式，用于提示
+const keyProtection = "文件权限（0600）"
+
+// protectKey 非Windows系统没有与DPAPI对应的通用接口，密钥以原文保存，仅依靠文件权限保护
+func protectKey(secret []byte) ([]byte, error) {
+	return secret, nil
+}
+
+// unprotectKey 返回密钥文件内容
+func unprotectKey(data []byte) (secret []byte, legacy bool, err error) {
+	return data, false, nil
+}
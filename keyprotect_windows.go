@@ -0,0 +1,41 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// keyProtection 密钥文件的保护方式，用于提示
+const keyProtection = "DPAPI（仅当前Windows用户可解密）"
+
+// protectKey 使用DPAPI以当前Windows用户身份加密密钥，复制到其他用户或其他电脑后无法解密
+func protectKey(secret []byte) ([]byte, error) {
+	in := windows.DataBlob{Size: uint32(len(secret)), Data: &secret[0]}
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, fmt.Errorf("DPAPI加密失败: %v", err)
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), nil
+}
+
+// unprotectKey 解密 protectKey 的结果；旧版本保存的未加密密钥（32字节）原样返回，legacy 为 true
+func unprotectKey(data []byte) (secret []byte, legacy bool, err error) {
+	if len(data) == 32 {
+		return data, true, nil
+	}
+	if len(data) == 0 {
+		return nil, false, fmt.Errorf("密钥文件为空")
+	}
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, false, fmt.Errorf("DPAPI解密失败（密钥文件可能来自其他用户或其他电脑）: %v", err)
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), false, nil
+}
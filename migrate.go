@@ -0,0 +1,535 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration 数据库结构迁移，按版本号顺序执行，每个迁移及其版本记录在同一事务中提交
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// migrations 全部迁移，只能在末尾追加，已发布的迁移不能再修改
+var migrations = []migration{
+	{1, "初始表结构", migrateBaseline},
+	{2, "规范化关注域名", normalizeWatchedDomains},
+	{3, "历史记录保存证书原文和探测详情", migrateHistoryDetails},
+	{4, "历史记录索引", migrateHistoryIndexes},
+	{5, "证书变更记录", migrateCertEvents},
+}
+
+// baselineTables 版本1的表结构，使用 CREATE TABLE IF NOT EXISTS 兼容引入版本化迁移之前创建的数据库
+var baselineTables = []struct{ name, ddl string }{
+	// 证书历史记录表
+	{"certificates", `
+		CREATE TABLE IF NOT EXISTS certificates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			domain TEXT NOT NULL,
+			port INTEGER DEFAULT 443,
+			protocol TEXT DEFAULT '',
+			issuer TEXT,
+			subject TEXT,
+			not_before DATETIME,
+			not_after DATETIME,
+			days_remaining INTEGER,
+			is_valid BOOLEAN,
+			status TEXT,
+			serial_number TEXT,
+			version INTEGER,
+			chain_valid BOOLEAN DEFAULT 1,
+			hostname_match BOOLEAN DEFAULT 1,
+			verify_errors TEXT,
+			connect_address TEXT DEFAULT '',
+			sni TEXT,
+			tls_grade TEXT DEFAULT '',
+			tls_scan TEXT,
+			key_algorithm TEXT,
+			key_size INTEGER,
+			signature_algorithm TEXT,
+			fingerprint_sha1 TEXT,
+			fingerprint_sha256 TEXT,
+			key_usage TEXT,
+			ext_key_usage TEXT,
+			basic_constraints TEXT,
+			weak_flags TEXT,
+			is_weak BOOLEAN DEFAULT 0,
+			query_time DATETIME DEFAULT (datetime('now', 'localtime'))
+		);
+	`},
+	// 关注域名表
+	{"watched_domains", `
+		CREATE TABLE IF NOT EXISTS watched_domains (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			domain TEXT NOT NULL,
+			port INTEGER NOT NULL DEFAULT 443,
+			protocol TEXT DEFAULT '',
+			nickname TEXT,
+			added_time DATETIME DEFAULT (datetime('now', 'localtime')),
+			last_check_time DATETIME,
+			notify_enabled BOOLEAN DEFAULT 0,
+			notify_threshold INTEGER DEFAULT 7,
+			is_manual BOOLEAN DEFAULT 0,
+			manual_expire_date DATETIME,
+			manual_start_date DATETIME,
+			trust_store_id INTEGER DEFAULT 0,
+			per_ip BOOLEAN DEFAULT 0,
+			connect_address TEXT DEFAULT '',
+			sni TEXT DEFAULT '',
+			no_sni BOOLEAN DEFAULT 0,
+			deep_scan BOOLEAN DEFAULT 0,
+			tls_grade TEXT DEFAULT '',
+			check_caa BOOLEAN DEFAULT 0,
+			check_dane BOOLEAN DEFAULT 0,
+			client_cert_id INTEGER DEFAULT 0,
+			proxy TEXT DEFAULT '',
+			check_http BOOLEAN DEFAULT 0,
+			warn_missing_hsts BOOLEAN DEFAULT 0,
+			UNIQUE(domain, port)
+		);
+	`},
+	// 自定义信任根表
+	{"trust_stores", `
+		CREATE TABLE IF NOT EXISTS trust_stores (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			pem TEXT NOT NULL,
+			is_global BOOLEAN DEFAULT 0,
+			created_time DATETIME DEFAULT (datetime('now', 'localtime'))
+		);
+	`},
+	// CRL缓存表（按颁发者和分发点缓存，nextUpdate之前不重复下载）
+	{"crl_cache", `
+		CREATE TABLE IF NOT EXISTS crl_cache (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			issuer TEXT NOT NULL,
+			url TEXT NOT NULL,
+			der BLOB NOT NULL,
+			this_update DATETIME,
+			next_update DATETIME,
+			revoked_count INTEGER DEFAULT 0,
+			fetched_time DATETIME DEFAULT (datetime('now', 'localtime')),
+			UNIQUE(issuer, url)
+		);
+	`},
+	// 客户端证书表（私钥使用应用数据目录中的密钥加密保存）
+	{"client_certificates", `
+		CREATE TABLE IF NOT EXISTS client_certificates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			cert_pem TEXT NOT NULL,
+			key_encrypted BLOB NOT NULL,
+			subject TEXT,
+			issuer TEXT,
+			not_before DATETIME,
+			not_after DATETIME,
+			notify_enabled BOOLEAN DEFAULT 1,
+			notify_threshold INTEGER DEFAULT 30,
+			created_time DATETIME DEFAULT (datetime('now', 'localtime'))
+		);
+	`},
+	// 设置表（键值对）
+	{"settings", `
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_time DATETIME DEFAULT (datetime('now', 'localtime'))
+		);
+	`},
+}
+
+// legacyColumns、legacyPortColumns 引入版本化迁移之前通过 ALTER TABLE 陆续添加的列，旧数据库可能缺少其中一部分；
+// 前者须在重建watched_domains表（支持端口）之前添加
+var legacyColumns = []struct{ table, column, definition string }{
+	{"watched_domains", "notify_enabled", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "notify_threshold", "INTEGER DEFAULT 7"},
+	{"watched_domains", "is_manual", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "manual_expire_date", "DATETIME"},
+	{"watched_domains", "manual_start_date", "DATETIME"},
+	{"certificates", "port", "INTEGER DEFAULT 443"},
+}
+
+var legacyPortColumns = []struct{ table, column, definition string }{
+	{"watched_domains", "protocol", "TEXT DEFAULT ''"},
+	{"certificates", "protocol", "TEXT DEFAULT ''"},
+	{"certificates", "chain_valid", "BOOLEAN DEFAULT 1"},
+	{"certificates", "hostname_match", "BOOLEAN DEFAULT 1"},
+	{"certificates", "verify_errors", "TEXT"},
+	{"watched_domains", "trust_store_id", "INTEGER DEFAULT 0"},
+	{"watched_domains", "per_ip", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "connect_address", "TEXT DEFAULT ''"},
+	{"watched_domains", "sni", "TEXT DEFAULT ''"},
+	{"watched_domains", "no_sni", "BOOLEAN DEFAULT 0"},
+	{"certificates", "connect_address", "TEXT DEFAULT ''"},
+	{"certificates", "sni", "TEXT"}, // 旧记录为NULL，查询时视为发送了域名
+	{"certificates", "tls_grade", "TEXT DEFAULT ''"},
+	{"certificates", "tls_scan", "TEXT"},
+	{"watched_domains", "deep_scan", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "tls_grade", "TEXT DEFAULT ''"},
+	{"certificates", "key_algorithm", "TEXT"},
+	{"certificates", "key_size", "INTEGER"},
+	{"certificates", "signature_algorithm", "TEXT"},
+	{"certificates", "fingerprint_sha1", "TEXT"},
+	{"certificates", "fingerprint_sha256", "TEXT"},
+	{"certificates", "key_usage", "TEXT"},
+	{"certificates", "ext_key_usage", "TEXT"},
+	{"certificates", "basic_constraints", "TEXT"},
+	{"certificates", "weak_flags", "TEXT"},
+	{"certificates", "is_weak", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "check_caa", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "check_dane", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "client_cert_id", "INTEGER DEFAULT 0"},
+	{"watched_domains", "proxy", "TEXT DEFAULT ''"},
+	{"watched_domains", "check_http", "BOOLEAN DEFAULT 0"},
+	{"watched_domains", "warn_missing_hsts", "BOOLEAN DEFAULT 0"},
+}
+
+// queryer 兼容 *sql.DB 和 *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SchemaStatus 数据库初始化和迁移状态
+type SchemaStatus struct {
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	Version    int    `json:"version"`              // 当前数据库版本
+	Latest     int    `json:"latest"`               // 程序支持的最新版本
+	DBPath     string `json:"dbPath,omitempty"`     // 数据库文件路径
+	BackupPath string `json:"backupPath,omitempty"` // 本次迁移前的备份
+	Error      string `json:"error,omitempty"`      // 初始化或迁移失败的原因
+}
+
+// latestSchemaVersion 返回程序支持的最新数据库版本
+func latestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// GetSchemaStatus 获取数据库初始化和迁移状态，界面启动时检查，失败时提示用户
+func (a *App) GetSchemaStatus() SchemaStatus {
+	status := a.schemaStatus
+	status.Latest = latestSchemaVersion()
+	status.Success = a.db != nil && status.Error == ""
+	switch {
+	case status.Error != "":
+		status.Message = "数据库初始化失败"
+	case a.db == nil:
+		status.Message = "数据库未初始化"
+	default:
+		status.Message = fmt.Sprintf("数据库版本 %d", status.Version)
+	}
+	return status
+}
+
+// migrate 执行尚未应用的迁移，返回迁移后的版本和迁移前的备份路径
+// dbPath 不为空且数据库已有数据时，先用 VACUUM INTO 备份到同一目录
+func (a *App) migrate(dbPath string) (int, string, error) {
+	_, err := a.db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_time DATETIME DEFAULT (datetime('now', 'localtime'))
+	);
+	`)
+	if err != nil {
+		return 0, "", fmt.Errorf("创建schema_version表失败: %v", err)
+	}
+
+	var version int
+	if err := a.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version); err != nil {
+		return 0, "", fmt.Errorf("读取数据库版本失败: %v", err)
+	}
+	if version > latestSchemaVersion() {
+		return version, "", fmt.Errorf("数据库版本 %d 高于当前程序支持的版本 %d，请使用新版本程序打开", version, latestSchemaVersion())
+	}
+	if version == latestSchemaVersion() {
+		return version, "", nil
+	}
+
+	backupPath := ""
+	if dbPath != "" {
+		existing, err := hasTable(a.db, "certificates")
+		if err != nil {
+			return version, "", err
+		}
+		if existing {
+			backupPath = fmt.Sprintf("%s.v%d-%s.bak", dbPath, version, time.Now().Format("20060102-150405"))
+			if _, err := a.db.Exec("VACUUM INTO ?", backupPath); err != nil {
+				return version, "", fmt.Errorf("迁移前备份数据库失败: %v", err)
+			}
+			fmt.Printf("✅ 迁移前已备份数据库: %s\n", backupPath)
+		}
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		if err := a.applyMigration(m); err != nil {
+			return version, backupPath, fmt.Errorf("迁移到版本 %d（%s）失败: %v", m.version, m.name, err)
+		}
+		version = m.version
+		fmt.Printf("✅ 数据库已迁移到版本 %d: %s\n", m.version, m.name)
+	}
+	return version, backupPath, nil
+}
+
+// applyMigration 在事务中执行单个迁移并记录版本，失败时整体回滚
+func (a *App) applyMigration(m migration) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_version (version, name) VALUES (?, ?)", m.version, m.name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// migrateBaseline 版本1：创建全部数据表，并为旧数据库补齐缺少的列
+func migrateBaseline(tx *sql.Tx) error {
+	for _, table := range baselineTables {
+		if _, err := tx.Exec(table.ddl); err != nil {
+			return fmt.Errorf("创建%s表失败: %v", table.name, err)
+		}
+	}
+
+	for _, c := range legacyColumns {
+		if err := addColumn(tx, c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	// 旧版watched_domains表的domain列为UNIQUE，需要重建以支持同一主机的不同端口
+	if err := migrateWatchedDomainsPort(tx); err != nil {
+		return fmt.Errorf("迁移watched_domains表失败: %v", err)
+	}
+
+	for _, c := range legacyPortColumns {
+		if err := addColumn(tx, c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateHistoryDetails 版本3：历史记录保存证书链PEM、SAN列表和探测信息（连接的IP、TLS版本、耗时），
+// 以及OCSP、CT、HTTP等检查结果（JSON）
+func migrateHistoryDetails(tx *sql.Tx) error {
+	columns := []struct{ column, definition string }{
+		{"pem", "TEXT"},
+		{"san_domains", "TEXT"},
+		{"san_ips", "TEXT"},
+		{"connected_ip", "TEXT DEFAULT ''"},
+		{"tls_version", "TEXT DEFAULT ''"},
+		{"duration_ms", "INTEGER DEFAULT 0"},
+		{"details", "TEXT"},
+	}
+	for _, c := range columns {
+		if err := addColumn(tx, "certificates", c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateHistoryIndexes 版本4：为历史记录的分页查询（按时间倒序）和按域名查询建立索引
+func migrateHistoryIndexes(tx *sql.Tx) error {
+	statements := []string{
+		"CREATE INDEX IF NOT EXISTS idx_certificates_domain_time ON certificates(domain, query_time)",
+		"CREATE INDEX IF NOT EXISTS idx_certificates_time ON certificates(query_time, id)",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("创建索引失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// migrateCertEvents 版本5：关注域名的证书快照（最近一次观察到的证书）和证书变更事件
+func migrateCertEvents(tx *sql.Tx) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS cert_snapshots (
+			watched_domain_id INTEGER PRIMARY KEY,
+			serial_number TEXT,
+			issuer TEXT,
+			public_key_sha256 TEXT,
+			san_list TEXT,
+			not_after TEXT,
+			fingerprint_sha256 TEXT,
+			first_seen_time DATETIME DEFAULT (datetime('now', 'localtime')),
+			checked_time DATETIME DEFAULT (datetime('now', 'localtime'))
+		)`,
+		`CREATE TABLE IF NOT EXISTS cert_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			watched_domain_id INTEGER NOT NULL,
+			kind TEXT NOT NULL,
+			old_value TEXT,
+			new_value TEXT,
+			unexpected BOOLEAN DEFAULT 0,
+			notified BOOLEAN DEFAULT 0,
+			fingerprint_sha256 TEXT,
+			event_time DATETIME DEFAULT (datetime('now', 'localtime'))
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_cert_events_domain_time ON cert_events(watched_domain_id, event_time)",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn 为数据表添加列，列已存在时跳过
+func addColumn(tx *sql.Tx, table, column, definition string) error {
+	exists, err := hasColumn(tx, table, column)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("为%s表添加%s列失败: %v", table, column, err)
+	}
+	return nil
+}
+
+// hasTable 检查数据表是否存在
+func hasTable(q queryer, table string) (bool, error) {
+	rows, err := q.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
+
+// normalizeWatchedDomains 将早期原样保存的关注域名（大小写、末尾的点、中文域名）转换为规范形式，
+// 与已有记录重复时保留原记录
+func normalizeWatchedDomains(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, domain, port FROM watched_domains")
+	if err != nil {
+		return err
+	}
+	type watchedRow struct {
+		id     int64
+		domain string
+		port   int
+	}
+	var pending []watchedRow
+	for rows.Next() {
+		var row watchedRow
+		if err := rows.Scan(&row.id, &row.domain, &row.port); err != nil {
+			rows.Close()
+			return err
+		}
+		pending = append(pending, row)
+	}
+	rows.Close()
+
+	for _, row := range pending {
+		host, err := normalizeHost(row.domain)
+		if err != nil || host == row.domain {
+			continue
+		}
+
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM watched_domains WHERE domain = ? AND port = ?", host, row.port).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			fmt.Printf("❌ 关注域名 %s 规范化后与已有的 %s 重复，保留原记录\n", row.domain, host)
+			continue
+		}
+		if _, err := tx.Exec("UPDATE watched_domains SET domain = ? WHERE id = ?", host, row.id); err != nil {
+			return err
+		}
+		fmt.Printf("✅ 关注域名已规范化: %s → %s\n", row.domain, host)
+	}
+	return nil
+}
+
+// migrateWatchedDomainsPort 为旧版watched_domains表添加port列，并将唯一约束改为(domain, port)
+func migrateWatchedDomainsPort(tx *sql.Tx) error {
+	hasPort, err := hasColumn(tx, "watched_domains", "port")
+	if err != nil {
+		return err
+	}
+	if hasPort {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE watched_domains_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			domain TEXT NOT NULL,
+			port INTEGER NOT NULL DEFAULT 443,
+			nickname TEXT,
+			added_time DATETIME DEFAULT (datetime('now', 'localtime')),
+			last_check_time DATETIME,
+			notify_enabled BOOLEAN DEFAULT 0,
+			notify_threshold INTEGER DEFAULT 7,
+			is_manual BOOLEAN DEFAULT 0,
+			manual_expire_date DATETIME,
+			manual_start_date DATETIME,
+			UNIQUE(domain, port)
+		)`,
+		`INSERT INTO watched_domains_new (
+			id, domain, port, nickname, added_time, last_check_time,
+			notify_enabled, notify_threshold, is_manual, manual_expire_date, manual_start_date
+		)
+		SELECT id, domain, 443, nickname, added_time, last_check_time,
+			notify_enabled, notify_threshold, is_manual, manual_expire_date, manual_start_date
+		FROM watched_domains`,
+		`DROP TABLE watched_domains`,
+		`ALTER TABLE watched_domains_new RENAME TO watched_domains`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("✅ watched_domains表已迁移，支持自定义端口")
+	return nil
+}
+
+// hasColumn 检查数据表是否包含指定列
+func hasColumn(q queryer, table, column string) (bool, error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   bool
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+
+	return false, rows.Err()
+}
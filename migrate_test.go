@@ -0,0 +1,173 @@
+package main
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// legacySchema 引入版本化迁移之前的早期数据库：watched_domains 没有端口且 domain 唯一，
+// 关注域名按用户输入原样保存
+const legacySchema = `
+CREATE TABLE certificates (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	domain TEXT NOT NULL,
+	issuer TEXT,
+	subject TEXT,
+	not_before DATETIME,
+	not_after DATETIME,
+	days_remaining INTEGER,
+	is_valid BOOLEAN,
+	status TEXT,
+	serial_number TEXT,
+	version INTEGER,
+	query_time DATETIME DEFAULT (datetime('now', 'localtime'))
+);
+CREATE TABLE watched_domains (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	domain TEXT NOT NULL UNIQUE,
+	nickname TEXT,
+	added_time DATETIME DEFAULT (datetime('now', 'localtime')),
+	last_check_time DATETIME
+);
+INSERT INTO certificates (domain, issuer, subject, not_after, days_remaining, is_valid, status, serial_number, version, query_time)
+VALUES ('example.com', 'R10', 'example.com', '2026-01-01 00:00:00', 30, 1, 'safe', '01', 3, '2025-06-01 10:00:00');
+INSERT INTO watched_domains (domain, nickname) VALUES ('Example.COM.', '官网');
+INSERT INTO watched_domains (domain, nickname) VALUES ('dup.example', '原记录');
+INSERT INTO watched_domains (domain, nickname) VALUES ('DUP.example', '重复');
+`
+
+// openTestDB 打开临时目录中的数据库文件
+func openTestDB(t *testing.T, dbPath string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newTestApp 返回使用临时数据库（已迁移到最新版本）的App
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "data.db")
+	a := &App{db: openTestDB(t, dbPath)}
+	if _, _, err := a.migrate(dbPath); err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	return a
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "data.db")
+	db := openTestDB(t, dbPath)
+	if _, err := db.Exec(legacySchema); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{db: db}
+	version, backupPath, err := a.migrate(dbPath)
+	if err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	if version != latestSchemaVersion() {
+		t.Fatalf("迁移后版本为 %d，期望 %d", version, latestSchemaVersion())
+	}
+	if backupPath == "" || !strings.Contains(backupPath, ".v0-") {
+		t.Fatalf("备份路径为 %q", backupPath)
+	}
+	if _, err := os.Stat(backupPath); err != nil {
+		t.Fatalf("迁移前的备份不存在: %v", err)
+	}
+
+	var applied int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Fatalf("记录了 %d 个迁移，期望 %d 个", applied, len(migrations))
+	}
+
+	// 补齐的列和新表
+	for _, c := range append(append(legacyColumns, legacyPortColumns...), struct{ table, column, definition string }{"certificates", "details", ""}) {
+		if ok, err := hasColumn(db, c.table, c.column); err != nil || !ok {
+			t.Errorf("%s表缺少%s列（%v）", c.table, c.column, err)
+		}
+	}
+	for _, table := range []string{"trust_stores", "crl_cache", "client_certificates", "settings", "cert_snapshots", "cert_events"} {
+		if ok, err := hasTable(db, table); err != nil || !ok {
+			t.Errorf("缺少%s表（%v）", table, err)
+		}
+	}
+
+	// 原有数据保留，关注域名规范化，重复的保留原样
+	rows, err := db.Query("SELECT domain, port, nickname, notify_threshold FROM watched_domains ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for rows.Next() {
+		var domain, nickname string
+		var port, threshold int
+		if err := rows.Scan(&domain, &port, &nickname, &threshold); err != nil {
+			t.Fatal(err)
+		}
+		if port != 443 || threshold != 7 {
+			t.Errorf("%s 的端口为 %d，提醒天数为 %d", domain, port, threshold)
+		}
+		got = append(got, domain+"/"+nickname)
+	}
+	rows.Close()
+	if want := "example.com/官网,dup.example/原记录,DUP.example/重复"; strings.Join(got, ",") != want {
+		t.Fatalf("关注域名为 %s，期望 %s", strings.Join(got, ","), want)
+	}
+
+	var port int
+	var details sql.NullString
+	if err := db.QueryRow("SELECT port, details FROM certificates WHERE domain = 'example.com'").Scan(&port, &details); err != nil {
+		t.Fatalf("历史记录未保留: %v", err)
+	}
+	if port != 443 || details.Valid {
+		t.Errorf("旧历史记录的端口为 %d，详情为 %v", port, details)
+	}
+
+	// 唯一约束改为(domain, port)
+	if _, err := db.Exec("INSERT INTO watched_domains (domain, port) VALUES ('example.com', 8443)"); err != nil {
+		t.Errorf("同一域名的不同端口应可以同时关注: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO watched_domains (domain, port) VALUES ('example.com', 443)"); err == nil {
+		t.Error("同一域名和端口不应重复关注")
+	}
+
+	// 已是最新版本时不再迁移和备份
+	version, backupPath, err = a.migrate(dbPath)
+	if err != nil || version != latestSchemaVersion() || backupPath != "" {
+		t.Fatalf("重复迁移返回 %d, %q, %v", version, backupPath, err)
+	}
+}
+
+func TestMigrateNewDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "data.db")
+	a := &App{db: openTestDB(t, dbPath)}
+	version, backupPath, err := a.migrate(dbPath)
+	if err != nil || version != latestSchemaVersion() {
+		t.Fatalf("新数据库迁移返回 %d, %v", version, err)
+	}
+	if backupPath != "" {
+		t.Errorf("新数据库不需要备份，实际备份到 %s", backupPath)
+	}
+}
+
+func TestMigrateNewerDatabase(t *testing.T) {
+	a := newTestApp(t)
+	if _, err := a.db.Exec("INSERT INTO schema_version (version, name) VALUES (?, 'future')", latestSchemaVersion()+1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := a.migrate(""); err == nil || !strings.Contains(err.Error(), "请使用新版本程序打开") {
+		t.Fatalf("数据库版本高于程序时应返回错误，得到 %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// OCSP查询结果
+const (
+	OCSPGood    = "good"
+	OCSPRevoked = "revoked"
+	OCSPUnknown = "unknown"
+)
+
+// OCSP响应来源
+const (
+	OCSPSourceStapled   = "stapled"   // 握手时服务器装订
+	OCSPSourceResponder = "responder" // 向证书AIA中的OCSP服务器查询
+)
+
+// maxOCSPResponseSize OCSP响应及颁发者证书下载的大小上限
+const maxOCSPResponseSize = 1 << 20
+
+// maxFetchDuration 单次吊销查询下载（包括读取响应）的最长时间
+const maxFetchDuration = 30 * time.Second
+
+// OCSPInfo 证书吊销状态（OCSP）
+type OCSPInfo struct {
+	Status           string `json:"status"`                     // "good", "revoked", "unknown"
+	Source           string `json:"source,omitempty"`           // "stapled", "responder"
+	Stapled          bool   `json:"stapled"`                    // 服务器是否装订了OCSP响应
+	ResponderURL     string `json:"responderUrl,omitempty"`     // 证书AIA中的OCSP服务器地址
+	ThisUpdate       string `json:"thisUpdate,omitempty"`       // 响应生成时间
+	NextUpdate       string `json:"nextUpdate,omitempty"`       // 响应有效期
+	RevokedAt        string `json:"revokedAt,omitempty"`        // 吊销时间
+	RevocationReason string `json:"revocationReason,omitempty"` // 吊销原因
+	Error            string `json:"error,omitempty"`            // 查询失败原因
+
+	sctList []byte // OCSP响应中携带的SCT列表（供CT检查使用）
+}
+
+// httpFetcher 返回OCSP、CRL等吊销查询使用的HTTP客户端：代理与探测相同（探测选项中的代理、全局代理或环境变量，
+// "direct" 时直连），连接和握手超时与探测设置相同；可替换为指向本地服务的客户端以便测试
+func (a *App) httpFetcher(opts ProbeOptions) *http.Client {
+	if a.httpClient != nil {
+		return a.httpClient
+	}
+	settings := a.currentSettings()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// HTTP/HTTPS代理由 Transport 转发请求，SOCKS5代理在拨号时处理（socks5:// 需要在本地解析域名）
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := a.proxyFor(opts, requestAddress(req.URL))
+		if err != nil || proxyURL == nil || isSOCKSProxy(proxyURL) {
+			return nil, err
+		}
+		return proxyURL, nil
+	}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialer := &net.Dialer{Timeout: settings.connectTimeout()}
+		proxyURL, err := a.proxyFor(opts, address)
+		if err != nil {
+			return nil, fmt.Errorf("无效的代理设置: %v", err)
+		}
+		if proxyURL == nil || !isSOCKSProxy(proxyURL) {
+			// 直连，或连接 Proxy 返回的HTTP代理
+			return dialer.DialContext(ctx, network, address)
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, settings.connectTimeout())
+		defer cancel()
+		return a.dialProxy(dialCtx, dialer, proxyURL, address)
+	}
+	transport.TLSHandshakeTimeout = settings.handshakeTimeout()
+	transport.ResponseHeaderTimeout = settings.handshakeTimeout()
+	return &http.Client{Timeout: maxFetchDuration, Transport: transport}
+}
+
+// requestAddress 返回URL对应的 host:port，未指定端口时使用协议默认端口
+func requestAddress(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "80"
+	if u.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// checkRevocation 检查证书吊销状态（OCSP和CRL），证书已吊销时状态改为 revoked（最高严重程度）；
+// 下载颁发者证书、OCSP查询和CRL下载随 ctx 取消，并使用与探测相同的代理
+func (a *App) checkRevocation(ctx context.Context, info *CertificateInfo, state *tls.ConnectionState, opts ProbeOptions) {
+	certs := state.PeerCertificates
+	issuer := a.findIssuer(ctx, certs[0], certs[1:], opts)
+
+	a.checkOCSP(ctx, info, state, issuer, opts)
+	a.checkCRLs(ctx, info, certs, issuer, opts)
+}
+
+// checkOCSP 检查叶子证书的OCSP状态：优先使用握手时装订的OCSP响应，没有时查询AIA中的OCSP服务器
+func (a *App) checkOCSP(ctx context.Context, info *CertificateInfo, state *tls.ConnectionState, issuer *x509.Certificate, opts ProbeOptions) {
+	leaf := state.PeerCertificates[0]
+
+	result := &OCSPInfo{Status: OCSPUnknown, Stapled: len(state.OCSPResponse) > 0}
+	if len(leaf.OCSPServer) > 0 {
+		result.ResponderURL = leaf.OCSPServer[0]
+	}
+	info.OCSP = result
+
+	var resp *ocsp.Response
+	var err error
+	if result.Stapled {
+		result.Source = OCSPSourceStapled
+		resp, err = parseOCSPResponse(state.OCSPResponse, leaf, issuer)
+		if err == nil && !resp.NextUpdate.IsZero() && time.Now().After(resp.NextUpdate) {
+			// 过期的装订响应不能说明证书当前的状态，改为查询OCSP服务器
+			stale := fmt.Sprintf("服务器装订的OCSP响应已过期（下次更新时间 %s）", resp.NextUpdate.Format("2006-01-02 15:04:05"))
+			info.Warnings = append(info.Warnings, stale)
+			if result.ResponderURL == "" || issuer == nil {
+				result.Error = stale
+				return
+			}
+			result.Source = OCSPSourceResponder
+			resp, err = a.queryOCSP(ctx, result.ResponderURL, leaf, issuer, opts)
+		}
+	} else if result.ResponderURL == "" {
+		result.Error = "服务器未装订OCSP响应，且证书未包含OCSP服务器地址"
+		return
+	} else if issuer == nil {
+		result.Error = "未找到颁发者证书，无法构造OCSP请求"
+		return
+	} else {
+		result.Source = OCSPSourceResponder
+		resp, err = a.queryOCSP(ctx, result.ResponderURL, leaf, issuer, opts)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	result.sctList = ocspSCTList(resp)
+	result.ThisUpdate = resp.ThisUpdate.Format("2006-01-02 15:04:05")
+	if !resp.NextUpdate.IsZero() {
+		result.NextUpdate = resp.NextUpdate.Format("2006-01-02 15:04:05")
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		result.Status = OCSPGood
+	case ocsp.Revoked:
+		result.Status = OCSPRevoked
+		result.RevokedAt = resp.RevokedAt.Format("2006-01-02 15:04:05")
+		result.RevocationReason = revocationReasonText(resp.RevocationReason)
+		markRevoked(info, fmt.Sprintf("证书已被吊销（OCSP）: %s，原因: %s", result.RevokedAt, result.RevocationReason))
+	default:
+		result.Status = OCSPUnknown
+	}
+}
+
+// markRevoked 将证书标记为已吊销
+func markRevoked(info *CertificateInfo, reason string) {
+	info.Status = StatusRevoked
+	info.IsValid = false
+	info.VerifyErrors = append(info.VerifyErrors, reason)
+}
+
+// findIssuer 查找叶子证书的颁发者：先在服务器发送的链中查找，找不到时下载AIA中的颁发者证书
+func (a *App) findIssuer(ctx context.Context, leaf *x509.Certificate, chain []*x509.Certificate, opts ProbeOptions) *x509.Certificate {
+	for _, cert := range chain {
+		if leaf.CheckSignatureFrom(cert) == nil {
+			return cert
+		}
+	}
+
+	for _, url := range leaf.IssuingCertificateURL {
+		cert, err := a.fetchIssuer(ctx, url, opts)
+		if err == nil && leaf.CheckSignatureFrom(cert) == nil {
+			return cert
+		}
+	}
+	return nil
+}
+
+// fetchIssuer 下载颁发者证书（DER或PEM格式）
+func (a *App) fetchIssuer(ctx context.Context, url string, opts ProbeOptions) (*x509.Certificate, error) {
+	resp, err := httpGet(ctx, a.httpFetcher(opts), url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载颁发者证书失败: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	return x509.ParseCertificate(data)
+}
+
+// queryOCSP 向OCSP服务器查询证书状态
+func (a *App) queryOCSP(ctx context.Context, url string, leaf, issuer *x509.Certificate, opts ProbeOptions) (*ocsp.Response, error) {
+	reqBody, err := ocsp.CreateRequest(leaf, issuer, &ocsp.RequestOptions{Hash: crypto.SHA1})
+	if err != nil {
+		return nil, fmt.Errorf("构造OCSP请求失败: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("构造OCSP请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("User-Agent", "SSL-Cert-Checker")
+	resp, err := a.httpFetcher(opts).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OCSP查询失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP查询失败: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("读取OCSP响应失败: %v", err)
+	}
+
+	return parseOCSPResponse(data, leaf, issuer)
+}
+
+// parseOCSPResponse 解析OCSP响应，有颁发者证书时同时校验响应签名
+func parseOCSPResponse(data []byte, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	resp, err := ocsp.ParseResponseForCert(data, leaf, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("解析OCSP响应失败: %v", err)
+	}
+	return resp, nil
+}
+
+// revocationReasonText 将RFC 5280吊销原因代码转换为说明
+func revocationReasonText(reason int) string {
+	switch reason {
+	case ocsp.Unspecified:
+		return "未指定"
+	case ocsp.KeyCompromise:
+		return "密钥泄露"
+	case ocsp.CACompromise:
+		return "CA密钥泄露"
+	case ocsp.AffiliationChanged:
+		return "隶属关系变更"
+	case ocsp.Superseded:
+		return "已被取代"
+	case ocsp.CessationOfOperation:
+		return "停止运营"
+	case ocsp.CertificateHold:
+		return "证书冻结"
+	case ocsp.RemoveFromCRL:
+		return "从CRL中移除"
+	case ocsp.PrivilegeWithdrawn:
+		return "权限撤销"
+	case ocsp.AACompromise:
+		return "AA密钥泄露"
+	}
+	return fmt.Sprintf("未知原因（%d）", reason)
+}
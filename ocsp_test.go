@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// testCert 测试用的证书及其私钥
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// newTestCert 签发测试证书，parent 为空时生成自签名CA证书
+func newTestCert(t *testing.T, name string, parent *testCert, dnsNames ...string) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		DNSNames:              dnsNames,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	signer, issuer := crypto.Signer(key), template
+	if parent != nil {
+		signer, issuer = parent.key, parent.cert
+	}
+	if len(dnsNames) == 0 {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+		template.ExtKeyUsage = nil
+	}
+	return signTestCert(t, template, key, issuer, signer)
+}
+
+// signTestCert 用颁发者的私钥签发模板对应的证书
+func signTestCert(t *testing.T, template *x509.Certificate, key crypto.Signer, issuer *x509.Certificate, signer crypto.Signer) *testCert {
+	t.Helper()
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// ocspResponder 测试用的OCSP服务器，同时提供颁发者证书下载
+type ocspResponder struct {
+	issuer   *testCert
+	signer   *testCert // 签名OCSP响应的证书，用于模拟签名错误
+	status   int
+	httpCode int
+	requests atomic.Int32
+}
+
+func (r *ocspResponder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/issuer.cer" {
+		w.Write(r.issuer.cert.Raw)
+		return
+	}
+
+	r.requests.Add(1)
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/ocsp-request" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if r.httpCode != 0 {
+		w.WriteHeader(r.httpCode)
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	ocspReq, err := ocsp.ParseRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().Truncate(time.Second)
+	template := ocsp.Response{
+		Status:       r.status,
+		SerialNumber: ocspReq.SerialNumber,
+		ThisUpdate:   now.Add(-time.Hour),
+		NextUpdate:   now.Add(24 * time.Hour),
+	}
+	if r.status == ocsp.Revoked {
+		template.RevokedAt = now.Add(-2 * time.Hour)
+		template.RevocationReason = ocsp.KeyCompromise
+	}
+	data, err := ocsp.CreateResponse(r.signer.cert, r.signer.cert, template, r.signer.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	w.Write(data)
+}
+
+// newTestLeafWithAIA 签发带有OCSP和颁发者证书地址的服务器证书
+func newTestLeafWithAIA(t *testing.T, issuer *testCert, ocspURL, issuerURL string) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(4242),
+		Subject:               pkix.Name{CommonName: "example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		DNSNames:              []string{"example.com"},
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	if ocspURL != "" {
+		template.OCSPServer = []string{ocspURL}
+	}
+	if issuerURL != "" {
+		template.IssuingCertificateURL = []string{issuerURL}
+	}
+	return signTestCert(t, template, key, issuer.cert, issuer.key)
+}
+
+func TestCheckOCSPResponder(t *testing.T) {
+	issuer := newTestCert(t, "Test CA", nil)
+	other := newTestCert(t, "Other CA", nil)
+	responder := &ocspResponder{issuer: issuer}
+	server := httptest.NewServer(responder)
+	defer server.Close()
+
+	leaf := newTestLeafWithAIA(t, issuer, server.URL+"/ocsp", server.URL+"/issuer.cer")
+	a := &App{httpClient: server.Client()}
+
+	tests := []struct {
+		name     string
+		status   int
+		httpCode int
+		signer   *testCert
+		chain    []*x509.Certificate // 服务器发送的证书链（不含叶子证书）
+		want     string
+		reason   string
+		wantErr  string
+	}{
+		{"正常", ocsp.Good, 0, issuer, []*x509.Certificate{issuer.cert}, OCSPGood, "", ""},
+		{"已吊销", ocsp.Revoked, 0, issuer, []*x509.Certificate{issuer.cert}, OCSPRevoked, "密钥泄露", ""},
+		{"未知", ocsp.Unknown, 0, issuer, []*x509.Certificate{issuer.cert}, OCSPUnknown, "", ""},
+		{"从AIA下载颁发者证书", ocsp.Good, 0, issuer, nil, OCSPGood, "", ""},
+		{"服务器错误", ocsp.Good, http.StatusInternalServerError, issuer, []*x509.Certificate{issuer.cert}, OCSPUnknown, "", "HTTP 500"},
+		{"响应签名错误", ocsp.Good, 0, other, []*x509.Certificate{issuer.cert}, OCSPUnknown, "", "解析OCSP响应失败"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responder.status, responder.httpCode, responder.signer = tt.status, tt.httpCode, tt.signer
+			state := &tls.ConnectionState{PeerCertificates: append([]*x509.Certificate{leaf.cert}, tt.chain...)}
+			info := &CertificateInfo{Status: StatusSafe, IsValid: true}
+			ctx := context.Background()
+
+			a.checkOCSP(ctx, info, state, a.findIssuer(ctx, leaf.cert, tt.chain, ProbeOptions{}), ProbeOptions{})
+			result := info.OCSP
+			if result.Source != OCSPSourceResponder || result.ResponderURL != server.URL+"/ocsp" {
+				t.Fatalf("来源为 %q，地址为 %q", result.Source, result.ResponderURL)
+			}
+			if result.Status != tt.want || !strings.Contains(result.Error, tt.wantErr) || (tt.wantErr == "" && result.Error != "") {
+				t.Fatalf("OCSP状态为 %s，错误为 %q；期望 %s, %q", result.Status, result.Error, tt.want, tt.wantErr)
+			}
+			if result.RevocationReason != tt.reason {
+				t.Errorf("吊销原因为 %q，期望 %q", result.RevocationReason, tt.reason)
+			}
+			if revoked := info.Status == StatusRevoked; revoked != (tt.want == OCSPRevoked) || revoked == info.IsValid {
+				t.Errorf("证书状态为 %s（有效 %v）", info.Status, info.IsValid)
+			}
+			if tt.wantErr == "" && (result.ThisUpdate == "" || result.NextUpdate == "") {
+				t.Errorf("缺少响应时间: %+v", result)
+			}
+		})
+	}
+}
+
+func TestCheckOCSPStapled(t *testing.T) {
+	issuer := newTestCert(t, "Test CA", nil)
+	responder := &ocspResponder{issuer: issuer, signer: issuer}
+	server := httptest.NewServer(responder)
+	defer server.Close()
+
+	leaf := newTestLeafWithAIA(t, issuer, server.URL+"/ocsp", "")
+	stapled, err := ocsp.CreateResponse(issuer.cert, issuer.cert, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: leaf.cert.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Hour),
+	}, issuer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{httpClient: server.Client()}
+	info := &CertificateInfo{}
+	state := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf.cert, issuer.cert}, OCSPResponse: stapled}
+	a.checkOCSP(context.Background(), info, state, issuer.cert, ProbeOptions{})
+	if info.OCSP.Status != OCSPGood || info.OCSP.Source != OCSPSourceStapled || !info.OCSP.Stapled {
+		t.Fatalf("装订的OCSP响应结果不正确: %+v", info.OCSP)
+	}
+	if n := responder.requests.Load(); n != 0 {
+		t.Fatalf("已装订OCSP响应时不应再查询OCSP服务器，实际查询了 %d 次", n)
+	}
+
+	// 装订的响应已过期时改为查询OCSP服务器
+	expired, err := ocsp.CreateResponse(issuer.cert, issuer.cert, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: leaf.cert.SerialNumber,
+		ThisUpdate:   time.Now().Add(-48 * time.Hour),
+		NextUpdate:   time.Now().Add(-24 * time.Hour),
+	}, issuer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responder.status = ocsp.Revoked
+	info = &CertificateInfo{}
+	state.OCSPResponse = expired
+	a.checkOCSP(context.Background(), info, state, issuer.cert, ProbeOptions{})
+	if info.OCSP.Status != OCSPRevoked || info.OCSP.Source != OCSPSourceResponder || len(info.Warnings) != 1 {
+		t.Fatalf("装订的OCSP响应过期时应查询OCSP服务器: %+v，警告 %v", info.OCSP, info.Warnings)
+	}
+	if n := responder.requests.Load(); n != 1 {
+		t.Fatalf("应查询OCSP服务器一次，实际查询了 %d 次", n)
+	}
+}
+
+func TestCheckOCSPWithoutResponder(t *testing.T) {
+	issuer := newTestCert(t, "Test CA", nil)
+	leaf := newTestLeafWithAIA(t, issuer, "", "")
+
+	a := &App{}
+	info := &CertificateInfo{}
+	a.checkOCSP(context.Background(), info, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf.cert}}, issuer.cert, ProbeOptions{})
+	if info.OCSP.Status != OCSPUnknown || !strings.Contains(info.OCSP.Error, "未包含OCSP服务器地址") {
+		t.Fatalf("没有OCSP服务器时结果不正确: %+v", info.OCSP)
+	}
+}
+
+func TestQueryOCSPCanceled(t *testing.T) {
+	issuer := newTestCert(t, "Test CA", nil)
+	blocked := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-blocked
+	}))
+	defer server.Close()
+	defer close(blocked)
+
+	leaf := newTestLeafWithAIA(t, issuer, server.URL, "")
+	a := &App{httpClient: server.Client()}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := a.queryOCSP(ctx, server.URL, leaf.cert, issuer.cert, ProbeOptions{}); err == nil {
+		t.Fatal("取消后应返回错误")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("取消后仍等待了 %v", elapsed)
+	}
+}
+
+func TestHTTPFetcherUsesProbeProxy(t *testing.T) {
+	// HTTP代理：收到绝对URI形式的请求
+	proxied := make(chan string, 1)
+	httpProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r.URL.Host
+		w.Write([]byte("ok"))
+	}))
+	defer httpProxy.Close()
+
+	a := &App{}
+	resp, err := httpGet(context.Background(), a.httpFetcher(ProbeOptions{Proxy: httpProxy.URL}), "http://ocsp.example.test/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := <-proxied; got != "ocsp.example.test" {
+		t.Fatalf("HTTP代理收到的主机为 %s", got)
+	}
+
+	// 探测选项中的SOCKS5代理，域名交给代理解析
+	server, requests := serveSOCKS5(t)
+	httpGet(context.Background(), a.httpFetcher(ProbeOptions{Proxy: "socks5h://" + server}), "http://crl.example.test/ca.crl")
+	if got := <-requests; got != "domain:crl.example.test" {
+		t.Fatalf("SOCKS5代理收到的目标为 %s", got)
+	}
+
+	// 未指定时使用全局代理
+	server, requests = serveSOCKS5(t)
+	a.settings, a.settingsLoaded = defaultSettings(), true
+	a.settings.Proxy = "socks5h://" + server
+	httpGet(context.Background(), a.httpFetcher(ProbeOptions{}), "http://aia.example.test/ca.cer")
+	if got := <-requests; got != "domain:aia.example.test" {
+		t.Fatalf("全局代理收到的目标为 %s", got)
+	}
+}
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// errProbeCanceled 探测被 CancelProbes 取消
+var errProbeCanceled = errors.New("查询已取消")
+
+// ProbeOptions 查询选项
+type ProbeOptions struct {
+	PerIP           bool   `json:"perIp"`                    // 解析所有A/AAAA记录，逐个IP探测（SNI仍为域名）
+	ConnectAddress  string `json:"connectAddress,omitempty"` // 实际连接的地址（IP或IP:端口），为空时连接目标本身
+	SNI             string `json:"sni,omitempty"`            // 覆盖握手时发送的SNI，为空时使用目标主机名
+	NoSNI           bool   `json:"noSni"`                    // 握手时不发送SNI
+	DeepScan        bool   `json:"deepScan"`                 // 深度扫描：探测支持的协议版本、加密套件、ALPN和曲线
+	CheckCAA        bool   `json:"checkCaa"`                 // 查询DNS CAA记录，检查是否允许当前颁发者签发
+	CheckDANE       bool   `json:"checkDane"`                // 查询TLSA记录（_端口._tcp.主机名），与证书链比对
+	ClientCertID    int64  `json:"clientCertId,omitempty"`   // 握手时出示的客户端证书（0表示不出示），关注域名通过 UpdateWatchedDomainClientCert 设置
+	Proxy           string `json:"proxy,omitempty"`          // 代理（http://、socks5://），为空时使用全局代理或环境变量，"direct" 表示直连
+	CheckHTTP       bool   `json:"checkHttp"`                // 握手后发送HTTP GET请求：HSTS、80端口重定向、重定向目标及其证书
+	WarnMissingHSTS bool   `json:"warnMissingHsts"`          // 未设置HSTS时警告（隐含 CheckHTTP）
+
+	Roots      *x509.CertPool   `json:"-"` // 校验证书链使用的信任根，nil表示系统根证书（由信任根设置解析得到）
+	ClientCert *tls.Certificate `json:"-"` // 由 ClientCertID 解析得到的客户端证书
+}
+
+// Resolver DNS解析接口，默认使用 net.DefaultResolver，可替换为本地DNS以便测试
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// IPProbeResult 逐IP探测时单个IP的结果
+type IPProbeResult struct {
+	IP                string `json:"ip"`
+	Success           bool   `json:"success"`
+	Error             string `json:"error,omitempty"`
+	SerialNumber      string `json:"serialNumber,omitempty"`
+	NotAfter          string `json:"notAfter,omitempty"`
+	DaysRemaining     int    `json:"daysRemaining"`
+	FingerprintSHA256 string `json:"fingerprintSha256,omitempty"`
+	Status            string `json:"status,omitempty"`
+}
+
+// serverName 返回握手时发送的SNI，不发送时为空
+func (o ProbeOptions) serverName(target Target) string {
+	if o.NoSNI {
+		return ""
+	}
+	if o.SNI != "" {
+		return o.SNI
+	}
+	return target.Host
+}
+
+// verifyName 返回校验证书时使用的主机名：指定了SNI时校验SNI，否则校验目标主机名
+func (o ProbeOptions) verifyName(target Target) string {
+	if o.SNI != "" {
+		return o.SNI
+	}
+	return target.Host
+}
+
+// dialAddress 返回实际拨号的地址，连接地址未带端口时使用目标端口
+func (o ProbeOptions) dialAddress(target Target) string {
+	if o.ConnectAddress == "" {
+		return target.Address()
+	}
+	if host, port, err := net.SplitHostPort(o.ConnectAddress); err == nil {
+		return net.JoinHostPort(host, port)
+	}
+	return net.JoinHostPort(strings.Trim(o.ConnectAddress, "[]"), strconv.Itoa(target.Port))
+}
+
+// normalize 校验并整理用户输入的选项
+func (o ProbeOptions) normalize() (ProbeOptions, error) {
+	o.ConnectAddress = strings.TrimSpace(o.ConnectAddress)
+	o.SNI = strings.TrimSpace(o.SNI)
+	if o.NoSNI {
+		o.SNI = ""
+	}
+
+	if o.ConnectAddress != "" {
+		host, port := strings.Trim(o.ConnectAddress, "[]"), ""
+		if h, p, err := net.SplitHostPort(o.ConnectAddress); err == nil {
+			host, port = h, p
+		}
+		if _, err := newTarget(host, port, ProtocolTLS); err != nil {
+			return o, fmt.Errorf("无效的连接地址 %s: %v", o.ConnectAddress, err)
+		}
+	}
+	if strings.ContainsAny(o.SNI, " /:") {
+		return o, fmt.Errorf("无效的SNI: %s", o.SNI)
+	}
+
+	proxy, err := normalizeProxy(o.Proxy)
+	if err != nil {
+		return o, err
+	}
+	o.Proxy = proxy
+	if o.WarnMissingHSTS {
+		o.CheckHTTP = true
+	}
+
+	return o, nil
+}
+
+// lookupResolver 返回当前使用的DNS解析器
+func (a *App) lookupResolver() Resolver {
+	if a.resolver != nil {
+		return a.resolver
+	}
+	return net.DefaultResolver
+}
+
+// probeContext 返回探测使用的上下文，调用 CancelProbes 或应用退出时取消
+func (a *App) probeContext() context.Context {
+	a.probeMu.Lock()
+	defer a.probeMu.Unlock()
+	if a.probeCtx == nil {
+		parent := a.ctx
+		if parent == nil {
+			parent = context.Background()
+		}
+		a.probeCtx, a.cancelProbes = context.WithCancel(parent)
+	}
+	return a.probeCtx
+}
+
+// CancelProbes 取消所有进行中的探测（单个查询、批量查询和关注列表刷新），之后开始的探测不受影响
+func (a *App) CancelProbes() {
+	a.probeMu.Lock()
+	defer a.probeMu.Unlock()
+	if a.cancelProbes != nil {
+		a.cancelProbes()
+		a.probeCtx, a.cancelProbes = nil, nil
+	}
+	fmt.Println("✅ 已取消进行中的探测")
+}
+
+// probeTarget 连接目标并获取证书信息（STARTTLS协议先完成明文协商）
+func (a *App) probeTarget(ctx context.Context, target Target, opts ProbeOptions) QueryResult {
+	// 指定了连接地址时只探测该地址
+	if opts.PerIP && opts.ConnectAddress == "" && net.ParseIP(target.Host) == nil {
+		return a.probeAllIPs(ctx, target, opts)
+	}
+
+	start := time.Now()
+	state, remoteIP, err := a.handshake(ctx, target, opts.dialAddress(target), opts)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("无法连接到 %s：%v", target, err),
+		}
+	}
+
+	certInfo := buildCertificateInfo(target, state.PeerCertificates, opts, time.Now())
+	certInfo.ConnectedIP = remoteIP
+	certInfo.TLSVersion = tls.VersionName(state.Version)
+	a.checkRevocation(ctx, certInfo, state, opts)
+	checkCT(certInfo, state)
+	a.checkDNSPolicies(ctx, certInfo, target, opts, state.PeerCertificates)
+	if opts.CheckHTTP && target.Protocol == ProtocolTLS {
+		a.checkHTTP(ctx, certInfo, target, opts)
+	}
+	if opts.DeepScan {
+		certInfo.TLSScan = a.scanTLS(ctx, target, opts.dialAddress(target), opts)
+	}
+	certInfo.DurationMs = time.Since(start).Milliseconds()
+
+	return QueryResult{
+		Success: true,
+		Message: "证书查询成功",
+		Data:    certInfo,
+	}
+}
+
+// checkDNSPolicies 按选项进行基于DNS记录的检查（CAA、DANE/TLSA）
+func (a *App) checkDNSPolicies(ctx context.Context, info *CertificateInfo, target Target, opts ProbeOptions, certs []*x509.Certificate) {
+	name := opts.verifyName(target)
+	if opts.CheckCAA && caaApplicable(name) {
+		a.checkCAA(ctx, info, name, certs[0])
+	}
+	if opts.CheckDANE && caaApplicable(name) {
+		a.checkDANE(ctx, info, name, target.Port, certs, opts.Roots)
+	}
+}
+
+// handshake 连接指定地址完成TLS握手，返回连接状态（证书链、装订的OCSP响应等）和实际连接的IP
+// 连接失败、超时或被重置时按设置的次数重试，每次重试前的等待时间翻倍
+func (a *App) handshake(ctx context.Context, target Target, address string, opts ProbeOptions) (*tls.ConnectionState, string, error) {
+	settings := a.currentSettings()
+	for attempt := 0; ; attempt++ {
+		state, remoteIP, err := a.handshakeWith(ctx, target, address, opts, nil)
+		if err == nil || attempt >= settings.Retries || !retryable(err) {
+			if err != nil && attempt > 0 {
+				err = fmt.Errorf("%w（已重试 %d 次）", err, attempt)
+			}
+			return state, remoteIP, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, remoteIP, errProbeCanceled
+		case <-time.After(settings.retryDelay(attempt + 1)):
+		}
+	}
+}
+
+// retryable 判断握手错误是否值得重试（网络错误、超时、连接被关闭），证书或协议错误重试无意义
+func retryable(err error) bool {
+	if errors.Is(err, errProbeCanceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// handshakeWith 与 handshake 相同但不重试，tune 不为nil时可在握手前调整TLS配置（限定协议版本、加密套件等）
+func (a *App) handshakeWith(ctx context.Context, target Target, address string, opts ProbeOptions, tune func(*tls.Config)) (*tls.ConnectionState, string, error) {
+	settings := a.currentSettings()
+
+	// 连接超时（经由代理时包括与代理的协商）
+	dialCtx, cancelDial := context.WithTimeout(ctx, settings.connectTimeout())
+	rawConn, proxied, err := a.dialTarget(dialCtx, address, opts)
+	cancelDial()
+	if err != nil {
+		return nil, "", probeError(ctx, err)
+	}
+	defer rawConn.Close()
+
+	// 经由代理时连接的对端是代理，只能从目标地址得到IP
+	remoteIP := ""
+	if !proxied {
+		if tcpAddr, ok := rawConn.RemoteAddr().(*net.TCPAddr); ok {
+			remoteIP = tcpAddr.IP.String()
+		}
+	} else if host, _, err := net.SplitHostPort(address); err == nil && net.ParseIP(host) != nil {
+		remoteIP = host
+	}
+
+	// 握手超时包括STARTTLS明文协商，避免服务器无响应时卡住；取消时立即中断读写
+	handshakeCtx, cancelHandshake := context.WithTimeout(ctx, settings.handshakeTimeout())
+	defer cancelHandshake()
+	deadline, _ := handshakeCtx.Deadline()
+	rawConn.SetDeadline(deadline)
+	stop := context.AfterFunc(handshakeCtx, func() { rawConn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if target.Protocol != ProtocolTLS {
+		if err := startTLS(rawConn, target.Protocol, opts.verifyName(target)); err != nil {
+			return nil, remoteIP, probeError(ctx, fmt.Errorf("STARTTLS协商失败: %w", err))
+		}
+	}
+
+	// 建立TLS连接
+	config := &tls.Config{
+		ServerName:         opts.serverName(target), // 为空时不发送SNI
+		InsecureSkipVerify: true,                    // 跳过证书验证，因为我们只关心获取证书信息
+	}
+	if opts.ClientCert != nil {
+		// 服务器请求客户端证书时总是出示，不按其可接受的CA列表筛选
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return opts.ClientCert, nil
+		}
+	}
+	if tune != nil {
+		tune(config)
+	}
+	conn := tls.Client(rawConn, config)
+	if err := conn.HandshakeContext(handshakeCtx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("TLS握手超时（%d秒）: %w", settings.HandshakeTimeout, err)
+		}
+		return nil, remoteIP, probeError(ctx, err)
+	}
+	defer conn.Close()
+
+	// 获取证书链（客户端握手时默认请求OCSP装订）
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, remoteIP, fmt.Errorf("服务器未返回证书")
+	}
+
+	return &state, remoteIP, nil
+}
+
+// probeError 探测被取消时统一返回 errProbeCanceled，其余错误原样返回
+func probeError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return errProbeCanceled
+	}
+	return err
+}
+
+// buildCertificateInfo 根据服务器证书链构建证书信息
+func buildCertificateInfo(target Target, certs []*x509.Certificate, opts ProbeOptions, now time.Time) *CertificateInfo {
+	// 获取第一个证书（服务器证书）
+	cert := certs[0]
+
+	// 获取SAN域名列表
+	var sanDomains []string
+	if len(cert.DNSNames) > 0 {
+		sanDomains = cert.DNSNames
+	}
+	var sanIPs []string
+	for _, ip := range cert.IPAddresses {
+		sanIPs = append(sanIPs, ip.String())
+	}
+
+	// 计算过期时间
+	expiryDate := cert.NotAfter
+	startDate := cert.NotBefore
+	daysRemaining := int(expiryDate.Sub(now).Hours() / 24)
+
+	// 握手时跳过了校验，这里单独校验证书链和主机名
+	verified := verifyCertificate(certs, opts.verifyName(target), opts.Roots, now)
+
+	// 分析完整证书链，链中最早的过期时间决定状态（中间证书先于叶子证书过期同样会导致访问失败）
+	chain := analyzeChain(certs, opts.Roots, now)
+	chainDaysRemaining := int(chain.EarliestExpiry.Sub(now).Hours() / 24)
+
+	// 判断证书状态：已过期优先，其次是校验失败，最后按剩余天数
+	status := statusForDays(chainDaysRemaining)
+	if status != StatusExpired {
+		if !verified.HostnameMatch {
+			status = StatusMismatch
+		} else if !verified.ChainValid {
+			status = StatusUntrusted
+		}
+	}
+
+	// 构建证书信息
+	info := &CertificateInfo{
+		Domain:        target.Host,
+		Port:          target.Port,
+		Protocol:      target.Protocol,
+		Issuer:        cert.Issuer.CommonName,
+		Subject:       cert.Subject.CommonName,
+		NotBefore:     startDate.Format("2006-01-02 15:04:05"),
+		NotAfter:      expiryDate.Format("2006-01-02 15:04:05"),
+		DaysRemaining: daysRemaining,
+		IsValid:       daysRemaining > 0 && verified.ChainValid && verified.HostnameMatch,
+		Status:        status,
+		SerialNumber:  cert.SerialNumber.String(),
+		Version:       cert.Version,
+		SANDomains:    sanDomains,
+		SANIPs:        sanIPs,
+		ChainValid:    verified.ChainValid,
+		HostnameMatch: verified.HostnameMatch,
+		VerifyErrors:  verified.Errors,
+
+		Chain:              chain.Chain,
+		ChainIssues:        chain.Issues,
+		ChainNotAfter:      chain.EarliestExpiry.Format("2006-01-02 15:04:05"),
+		ChainDaysRemaining: chainDaysRemaining,
+		PEM:                encodeCertificatesPEM(certs),
+
+		ConnectAddress: opts.ConnectAddress,
+		SNI:            opts.serverName(target),
+	}
+	applyCertificateDetails(info, cert)
+	return info
+}
+
+// encodeCertificatesPEM 将证书链编码为PEM格式
+func encodeCertificatesPEM(certs []*x509.Certificate) string {
+	var buf strings.Builder
+	for _, cert := range certs {
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.String()
+}
+
+// probeAllIPs 解析域名的所有A/AAAA记录，逐个IP握手（SNI为域名），
+// 以状态最差的节点作为域名的证书信息，并在节点之间证书不一致时标记
+func (a *App) probeAllIPs(ctx context.Context, target Target, opts ProbeOptions) QueryResult {
+	start := time.Now()
+	lookupCtx, cancel := context.WithTimeout(ctx, a.currentSettings().connectTimeout())
+	defer cancel()
+
+	addrs, err := a.lookupResolver().LookupIPAddr(lookupCtx, target.Host)
+	if err != nil {
+		err = probeError(ctx, err)
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("解析 %s 失败：%v", target.Host, err),
+		}
+	}
+	if len(addrs) == 0 {
+		return QueryResult{
+			Success: false,
+			Error:   "未解析到任何IP地址",
+			Message: fmt.Sprintf("解析 %s 失败：未解析到任何IP地址", target.Host),
+		}
+	}
+
+	// 并发探测每个IP，结果保持解析顺序
+	now := time.Now()
+	infos := make([]*CertificateInfo, len(addrs))
+	chains := make([][]*x509.Certificate, len(addrs))
+	results := make([]IPProbeResult, len(addrs))
+
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func(index int, ip string) {
+			defer wg.Done()
+
+			results[index].IP = ip
+			state, _, err := a.handshake(ctx, target, net.JoinHostPort(ip, strconv.Itoa(target.Port)), opts)
+			if err != nil {
+				results[index].Error = err.Error()
+				return
+			}
+
+			info := buildCertificateInfo(target, state.PeerCertificates, opts, now)
+			info.ConnectedIP = ip
+			info.TLSVersion = tls.VersionName(state.Version)
+			a.checkRevocation(ctx, info, state, opts)
+			checkCT(info, state)
+			infos[index] = info
+			chains[index] = state.PeerCertificates
+			results[index].Success = true
+			results[index].SerialNumber = info.SerialNumber
+			results[index].NotAfter = info.NotAfter
+			results[index].DaysRemaining = info.DaysRemaining
+			results[index].FingerprintSHA256 = fingerprintSHA256(state.PeerCertificates[0])
+			results[index].Status = info.Status
+		}(i, addr.IP.String())
+	}
+	wg.Wait()
+
+	// 选出状态最差的节点，并检查各节点的序列号和过期时间是否一致
+	var worst *CertificateInfo
+	var worstIndex int
+	var failed []string
+	serials := make(map[string]bool)
+	expiries := make(map[string]bool)
+	for i, info := range infos {
+		if info == nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", results[i].IP, results[i].Error))
+			continue
+		}
+		serials[info.SerialNumber] = true
+		expiries[info.NotAfter] = true
+		if worst == nil || statusSeverity(info.Status) > statusSeverity(worst.Status) ||
+			(info.Status == worst.Status && info.DaysRemaining < worst.DaysRemaining) {
+			worst, worstIndex = info, i
+		}
+	}
+
+	if worst == nil {
+		return QueryResult{
+			Success: false,
+			Error:   strings.Join(failed, "; "),
+			Message: fmt.Sprintf("无法连接到 %s 的任何IP：%s", target, strings.Join(failed, "; ")),
+		}
+	}
+
+	worst.IPResults = results
+	a.checkDNSPolicies(ctx, worst, target, opts, chains[worstIndex])
+	if opts.CheckHTTP && target.Protocol == ProtocolTLS {
+		a.checkHTTP(ctx, worst, target, opts)
+	}
+	if opts.DeepScan {
+		worst.TLSScan = a.scanTLS(ctx, target, net.JoinHostPort(worst.ConnectedIP, strconv.Itoa(target.Port)), opts)
+	}
+	if len(serials) > 1 || len(expiries) > 1 {
+		worst.IPInconsistent = true
+		worst.Warnings = append(worst.Warnings,
+			fmt.Sprintf("各节点证书不一致：%d 个不同序列号，%d 个不同过期时间", len(serials), len(expiries)))
+	}
+	worst.DurationMs = time.Since(start).Milliseconds()
+	for _, f := range failed {
+		worst.Warnings = append(worst.Warnings, "节点探测失败 "+f)
+	}
+
+	return QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("证书查询成功（共 %d 个IP）", len(addrs)),
+		Data:    worst,
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// ProxyDirect 关注域名或单次查询的代理设置为该值时不使用任何代理（包括全局代理和环境变量）
+const ProxyDirect = "direct"
+
+// ProxySettingsResult 代理设置查询结果
+type ProxySettingsResult struct {
+	Success     bool   `json:"success"`
+	Message     string `json:"message"`
+	Proxy       string `json:"proxy"`                 // 全局代理（为空时使用环境变量）
+	Environment string `json:"environment,omitempty"` // 环境变量 HTTPS_PROXY（密码已隐藏）
+	NoProxy     string `json:"noProxy,omitempty"`     // 环境变量 NO_PROXY
+	Error       string `json:"error,omitempty"`
+}
+
+// normalizeProxy 校验代理地址：支持 http://、https://（与代理之间使用TLS）、socks5://（本地解析目标域名）、
+// socks5h://（由代理解析目标域名），可带 用户名:密码@，必须指定端口
+func normalizeProxy(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, ProxyDirect) {
+		return strings.ToLower(raw), nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("无效的代理地址 %s: %v", raw, err)
+	}
+	if !supportedProxyScheme(u.Scheme) {
+		return "", fmt.Errorf("不支持的代理协议 %q（支持 http、https、socks5、socks5h）", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return "", fmt.Errorf("代理地址需包含主机和端口，如 socks5://127.0.0.1:1080")
+	}
+	return u.String(), nil
+}
+
+// supportedProxyScheme 判断是否为支持的代理协议
+func supportedProxyScheme(scheme string) bool {
+	switch scheme {
+	case "http", "https", "socks5", "socks5h":
+		return true
+	}
+	return false
+}
+
+// isSOCKSProxy 判断是否为SOCKS5代理
+func isSOCKSProxy(proxyURL *url.URL) bool {
+	return proxyURL.Scheme == "socks5" || proxyURL.Scheme == "socks5h"
+}
+
+// redactProxy 隐藏代理地址中的密码，用于日志和展示
+func redactProxy(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// redactedPassword 隐藏后的代理密码，与 url.URL.Redacted 一致
+const redactedPassword = "xxxxx"
+
+// keepProxyPassword 前端提交的代理为隐藏了密码的已保存代理时（协议、用户名和主机相同），沿用已保存的密码
+func keepProxyPassword(proxy, saved string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	if password, ok := u.User.Password(); !ok || password != redactedPassword {
+		return proxy
+	}
+	old, err := url.Parse(saved)
+	if err != nil || old.User == nil || old.Scheme != u.Scheme || old.Host != u.Host || old.User.Username() != u.User.Username() {
+		return proxy
+	}
+	return saved
+}
+
+// encryptedProxyPrefix 加密保存的代理地址前缀
+const encryptedProxyPrefix = "enc:"
+
+// sealProxy 带密码的代理地址使用客户端证书私钥的加密密钥加密后保存，不带密码的原样保存
+func sealProxy(proxy string) (string, error) {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy, nil
+	}
+	if _, ok := u.User.Password(); !ok {
+		return proxy, nil
+	}
+	data, err := encryptSecret([]byte(proxy))
+	if err != nil {
+		return "", fmt.Errorf("加密代理密码失败: %v", err)
+	}
+	return encryptedProxyPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// openProxy 解密 sealProxy 保存的代理地址，未加密的（包括旧版本保存的）原样返回
+func openProxy(stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedProxyPrefix) {
+		return stored, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedProxyPrefix))
+	if err != nil {
+		return "", fmt.Errorf("解密代理密码失败: %v", err)
+	}
+	plaintext, err := decryptSecret(data)
+	if err != nil {
+		return "", fmt.Errorf("解密代理密码失败: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// proxyFor 返回连接指定地址使用的代理，nil表示直连
+// 优先级：探测选项中的代理 > 全局代理 > 环境变量 HTTPS_PROXY；后两者遵循 NO_PROXY
+func (a *App) proxyFor(opts ProbeOptions, address string) (*url.URL, error) {
+	if opts.Proxy == ProxyDirect {
+		return nil, nil
+	}
+	if opts.Proxy != "" {
+		return url.Parse(opts.Proxy)
+	}
+	return a.proxyConfig().ProxyFunc()(&url.URL{Scheme: "https", Host: address})
+}
+
+// proxyConfig 返回环境变量中的代理配置，设置了全局代理时以全局代理替换 HTTP_PROXY/HTTPS_PROXY
+func (a *App) proxyConfig() *httpproxy.Config {
+	config := httpproxy.FromEnvironment()
+	if global := a.globalProxy(); global != "" {
+		config.HTTPSProxy = global
+		config.HTTPProxy = global
+	}
+	return config
+}
+
+// globalProxy 返回全局代理设置
+func (a *App) globalProxy() string {
+	return a.currentSettings().Proxy
+}
+
+// GetProxySettings 获取全局代理设置及环境变量中的代理
+func (a *App) GetProxySettings() ProxySettingsResult {
+	return ProxySettingsResult{
+		Success:     true,
+		Message:     "查询代理设置成功",
+		Proxy:       redactProxy(a.globalProxy()),
+		Environment: redactProxy(firstEnv("HTTPS_PROXY", "https_proxy")),
+		NoProxy:     firstEnv("NO_PROXY", "no_proxy"),
+	}
+}
+
+// firstEnv 返回第一个非空的环境变量
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// dialTarget 连接目标地址，按设置经由HTTP CONNECT或SOCKS5代理，返回的 proxied 表示是否经由代理
+func (a *App) dialTarget(ctx context.Context, address string, opts ProbeOptions) (conn net.Conn, proxied bool, err error) {
+	proxyURL, err := a.proxyFor(opts, address)
+	if err != nil {
+		return nil, false, fmt.Errorf("无效的代理设置: %v", err)
+	}
+
+	var dialer net.Dialer
+	if proxyURL == nil {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+		return conn, false, err
+	}
+	if !supportedProxyScheme(proxyURL.Scheme) {
+		// 环境变量中的代理未经 normalizeProxy 校验
+		return nil, true, fmt.Errorf("代理 %s 使用不支持的协议 %q（支持 http、https、socks5、socks5h），可在设置中指定全局代理或对该目标设置 %s",
+			redactProxy(proxyURL.String()), proxyURL.Scheme, ProxyDirect)
+	}
+	conn, err = a.dialProxy(ctx, &dialer, proxyURL, address)
+	return conn, true, err
+}
+
+// dialProxy 经由代理连接目标地址
+func (a *App) dialProxy(ctx context.Context, dialer *net.Dialer, proxyURL *url.URL, address string) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		// socks5:// 在本地解析目标域名后把IP发给代理，socks5h:// 把域名交给代理解析
+		if proxyURL.Scheme == "socks5" {
+			resolved, err := a.resolveForProxy(ctx, address)
+			if err != nil {
+				return nil, err
+			}
+			address = resolved
+		}
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+		socks, err := proxy.SOCKS5("tcp", proxyURL.Host, auth, dialer)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := socks.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, fmt.Errorf("通过SOCKS5代理 %s 连接失败: %w", proxyURL.Host, err)
+		}
+		return conn, nil
+	case "http", "https":
+		return dialHTTPConnect(ctx, dialer, proxyURL, address)
+	}
+	return nil, fmt.Errorf("不支持的代理协议 %q", proxyURL.Scheme)
+}
+
+// resolveForProxy 在本地解析目标地址中的域名，返回 IP:端口
+func (a *App) resolveForProxy(ctx context.Context, address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return address, nil
+	}
+	addrs, err := a.lookupResolver().LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", fmt.Errorf("解析 %s 失败（socks5:// 代理在本地解析域名，由代理解析请使用 socks5h://）: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("解析 %s 失败: 没有IP地址", host)
+	}
+	return net.JoinHostPort(addrs[0].IP.String(), port), nil
+}
+
+// dialHTTPConnect 通过HTTP代理的CONNECT方法建立隧道，https:// 代理先与代理建立TLS连接
+func dialHTTPConnect(ctx context.Context, dialer *net.Dialer, proxyURL *url.URL, address string) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("连接HTTP代理 %s 失败: %w", proxyURL.Host, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("与HTTPS代理 %s 建立TLS连接失败: %w", proxyURL.Host, err)
+		}
+		conn = tlsConn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("发送CONNECT请求失败: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("读取CONNECT响应失败: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		if resp.StatusCode == http.StatusProxyAuthRequired {
+			return nil, fmt.Errorf("HTTP代理 %s 需要认证或认证失败", proxyURL.Host)
+		}
+		return nil, fmt.Errorf("HTTP代理 %s 拒绝CONNECT: %s", proxyURL.Host, resp.Status)
+	}
+
+	conn.SetDeadline(time.Time{})
+	// 读取响应时可能已缓冲了隧道中的数据，后续读取先从缓冲区读
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// bufferedConn 先从缓冲区读取的连接
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// retentionInterval 定期清理历史记录的间隔（启动时先执行一次）
+const retentionInterval = 24 * time.Hour
+
+// RetentionPolicy 历史记录保留策略（保存在 settings 表），各项为0或false时不限制
+type RetentionPolicy struct {
+	MaxAgeDays       int  `json:"maxAgeDays"`       // 删除查询时间早于N天的记录
+	MaxRowsPerTarget int  `json:"maxRowsPerTarget"` // 每个目标（域名+端口+协议）最多保留的记录数，超出时删除最早的
+	KeepOnlyChanges  bool `json:"keepOnlyChanges"`  // 仅保留证书（序列号或过期时间）发生变化的记录，每个目标的最新记录始终保留
+}
+
+// PruneReport 历史记录清理结果
+type PruneReport struct {
+	Time       string `json:"time"`       // 清理时间
+	ByAge      int64  `json:"byAge"`      // 超过保留天数删除的记录数
+	Unchanged  int64  `json:"unchanged"`  // 证书未变化删除的记录数
+	OverLimit  int64  `json:"overLimit"`  // 超过每个目标最多记录数删除的记录数
+	Remaining  int    `json:"remaining"`  // 清理后剩余的记录数
+	Vacuumed   bool   `json:"vacuumed"`   // 是否执行了VACUUM
+	SizeBefore int64  `json:"sizeBefore"` // VACUUM前的数据库大小（字节）
+	SizeAfter  int64  `json:"sizeAfter"`  // VACUUM后的数据库大小（字节）
+}
+
+// RetentionPolicyResult 保留策略查询结果
+type RetentionPolicyResult struct {
+	Success   bool            `json:"success"`
+	Message   string          `json:"message"`
+	Policy    RetentionPolicy `json:"policy"`
+	Defaults  RetentionPolicy `json:"defaults"`            // 默认策略，用于恢复默认
+	LastPrune *PruneReport    `json:"lastPrune,omitempty"` // 最近一次清理的结果
+	Error     string          `json:"error,omitempty"`
+}
+
+// PruneResult 手动清理结果
+type PruneResult struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Report  *PruneReport `json:"report,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// defaultRetentionPolicy 返回默认保留策略（永久保留）
+func defaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{}
+}
+
+// validate 校验保留策略的取值范围
+func (p RetentionPolicy) validate() error {
+	if p.MaxAgeDays < 0 || p.MaxAgeDays > 3650 {
+		return fmt.Errorf("保留天数应在 0-3650 之间（0表示永久保留）")
+	}
+	if p.MaxRowsPerTarget < 0 || p.MaxRowsPerTarget > 100000 {
+		return fmt.Errorf("每个目标的记录数应在 0-100000 之间（0表示不限制）")
+	}
+	return nil
+}
+
+// summary 返回清理结果的说明
+func (r *PruneReport) summary() string {
+	message := fmt.Sprintf("删除 %d 条记录（超过保留天数 %d，证书未变化 %d，超过数量上限 %d），剩余 %d 条",
+		r.ByAge+r.Unchanged+r.OverLimit, r.ByAge, r.Unchanged, r.OverLimit, r.Remaining)
+	if r.Vacuumed {
+		message += fmt.Sprintf("，数据库由 %.1f MB 压缩到 %.1f MB", float64(r.SizeBefore)/1048576, float64(r.SizeAfter)/1048576)
+	}
+	return message
+}
+
+// loadRetentionPolicy 从设置表读取保留策略，缺失或无效时使用默认策略
+func (a *App) loadRetentionPolicy() (RetentionPolicy, error) {
+	policy := defaultRetentionPolicy()
+	for key, field := range map[string]*int{
+		settingHistoryRetentionDays: &policy.MaxAgeDays,
+		settingHistoryMaxRows:       &policy.MaxRowsPerTarget,
+	} {
+		value, err := a.getSetting(key)
+		if err != nil {
+			return policy, err
+		}
+		if n, err := strconv.Atoi(value); err == nil {
+			*field = n
+		}
+	}
+
+	value, err := a.getSetting(settingHistoryKeepChanges)
+	if err != nil {
+		return policy, err
+	}
+	policy.KeepOnlyChanges = value == "1"
+
+	if err := policy.validate(); err != nil {
+		fmt.Printf("❌ 历史记录保留策略无效，使用默认策略: %v\n", err)
+		return defaultRetentionPolicy(), nil
+	}
+	return policy, nil
+}
+
+// GetRetentionPolicy 获取历史记录保留策略及最近一次清理的结果
+func (a *App) GetRetentionPolicy() RetentionPolicyResult {
+	if a.db == nil {
+		return RetentionPolicyResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	policy, err := a.loadRetentionPolicy()
+	if err != nil {
+		return RetentionPolicyResult{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	a.lastPruneMu.Lock()
+	lastPrune := a.lastPrune
+	a.lastPruneMu.Unlock()
+
+	return RetentionPolicyResult{
+		Success:   true,
+		Message:   "查询保留策略成功",
+		Policy:    policy,
+		Defaults:  defaultRetentionPolicy(),
+		LastPrune: lastPrune,
+	}
+}
+
+// UpdateRetentionPolicy 更新历史记录保留策略，在下次定期清理（或手动清理）时生效
+func (a *App) UpdateRetentionPolicy(policy RetentionPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	keepChanges := "0"
+	if policy.KeepOnlyChanges {
+		keepChanges = "1"
+	}
+	values := map[string]string{
+		settingHistoryRetentionDays: strconv.Itoa(policy.MaxAgeDays),
+		settingHistoryMaxRows:       strconv.Itoa(policy.MaxRowsPerTarget),
+		settingHistoryKeepChanges:   keepChanges,
+	}
+	if err := a.setSettings(values); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ 更新历史记录保留策略: 保留 %d 天，每个目标最多 %d 条，仅保留变化 %v\n",
+		policy.MaxAgeDays, policy.MaxRowsPerTarget, policy.KeepOnlyChanges)
+	return nil
+}
+
+// PruneHistory 按保留策略立即清理历史记录，vacuum 为 true 时随后执行VACUUM回收磁盘空间
+func (a *App) PruneHistory(vacuum bool) PruneResult {
+	report, err := a.pruneHistory(vacuum)
+	if err != nil {
+		return PruneResult{
+			Success: false,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("清理历史记录失败：%v", err),
+		}
+	}
+	return PruneResult{
+		Success: true,
+		Message: report.summary(),
+		Report:  report,
+	}
+}
+
+// pruneHistory 在一个事务中依次按保留天数、证书变化、每个目标的记录数清理历史记录
+func (a *App) pruneHistory(vacuum bool) (*PruneReport, error) {
+	if a.db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+
+	a.pruneMu.Lock()
+	defer a.pruneMu.Unlock()
+
+	policy, err := a.loadRetentionPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PruneReport{Time: time.Now().Format("2006-01-02 15:04:05")}
+	tx, err := a.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if policy.MaxAgeDays > 0 {
+		result, err := tx.Exec("DELETE FROM certificates WHERE query_time < datetime('now', 'localtime', ?)",
+			fmt.Sprintf("-%d days", policy.MaxAgeDays))
+		if err != nil {
+			return nil, fmt.Errorf("按保留天数清理失败: %v", err)
+		}
+		report.ByAge, _ = result.RowsAffected()
+	}
+
+	if policy.KeepOnlyChanges {
+		// 与同一目标的上一条记录证书相同（序列号和过期时间都未变化）的记录，最新一条除外
+		result, err := tx.Exec(`
+		DELETE FROM certificates WHERE id IN (
+			SELECT id FROM (
+				SELECT id, serial_number, not_after,
+				       LAG(serial_number) OVER w AS prev_serial,
+				       LAG(not_after) OVER w AS prev_not_after,
+				       LEAD(id) OVER w AS next_id
+				FROM certificates
+				WINDOW w AS (PARTITION BY domain, port, protocol ORDER BY query_time, id)
+			)
+			WHERE prev_serial IS serial_number AND prev_not_after IS not_after AND next_id IS NOT NULL
+		)`)
+		if err != nil {
+			return nil, fmt.Errorf("清理未变化的记录失败: %v", err)
+		}
+		report.Unchanged, _ = result.RowsAffected()
+	}
+
+	if policy.MaxRowsPerTarget > 0 {
+		result, err := tx.Exec(`
+		DELETE FROM certificates WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY domain, port, protocol ORDER BY query_time DESC, id DESC) AS n
+				FROM certificates
+			)
+			WHERE n > ?
+		)`, policy.MaxRowsPerTarget)
+		if err != nil {
+			return nil, fmt.Errorf("按记录数清理失败: %v", err)
+		}
+		report.OverLimit, _ = result.RowsAffected()
+	}
+
+	if err := tx.QueryRow("SELECT COUNT(*) FROM certificates").Scan(&report.Remaining); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// VACUUM不能在事务中执行
+	if vacuum {
+		report.SizeBefore, _ = a.databaseSize()
+		if _, err := a.db.Exec("VACUUM"); err != nil {
+			return nil, fmt.Errorf("压缩数据库失败: %v", err)
+		}
+		report.Vacuumed = true
+		report.SizeAfter, _ = a.databaseSize()
+	}
+
+	a.lastPruneMu.Lock()
+	a.lastPrune = report
+	a.lastPruneMu.Unlock()
+	fmt.Printf("✅ 清理历史记录: %s\n", report.summary())
+	return report, nil
+}
+
+// databaseSize 返回数据库占用的空间（字节）
+func (a *App) databaseSize() (int64, error) {
+	var pageCount, pageSize int64
+	if err := a.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, err
+	}
+	if err := a.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return pageCount * pageSize, nil
+}
+
+// runRetention 启动时清理一次历史记录，之后每隔 retentionInterval 清理一次，应用退出时停止
+func (a *App) runRetention() {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.pruneHistory(false); err != nil {
+			fmt.Printf("❌ 清理历史记录失败: %v\n", err)
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
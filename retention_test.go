@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateRetentionPolicy(t *testing.T) {
+	a := newTestApp(t)
+	want := RetentionPolicy{MaxAgeDays: 30, MaxRowsPerTarget: 100, KeepOnlyChanges: true}
+	if err := a.UpdateRetentionPolicy(want); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := a.loadRetentionPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy != want {
+		t.Fatalf("保留策略为 %+v，期望 %+v", policy, want)
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	a := newTestApp(t)
+	for _, queryTime := range []string{"2020-01-01 10:00:00", "2020-01-02 10:00:00", "2099-01-01 10:00:00", "2099-01-02 10:00:00", "2099-01-03 10:00:00"} {
+		insertHistory(t, a, "example.com", queryTime)
+	}
+	if err := a.UpdateRetentionPolicy(RetentionPolicy{MaxAgeDays: 30, KeepOnlyChanges: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := a.pruneHistory(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 两条超过保留天数；剩余三条证书相同，保留首次和最新的记录
+	if report.ByAge != 2 || report.Unchanged != 1 || report.Remaining != 2 {
+		t.Fatalf("清理结果不正确: %+v", report)
+	}
+
+	// 清理进行中（如执行VACUUM时）仍可查询保留策略和上次清理结果
+	a.pruneMu.Lock()
+	defer a.pruneMu.Unlock()
+	done := make(chan RetentionPolicyResult, 1)
+	go func() { done <- a.GetRetentionPolicy() }()
+	select {
+	case result := <-done:
+		if result.LastPrune != report {
+			t.Fatalf("上次清理结果为 %+v，期望 %+v", result.LastPrune, report)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("清理进行中查询保留策略被阻塞")
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 设置项键名
+const (
+	settingProxy            = "proxy"             // 全局代理
+	settingConnectTimeout   = "connect_timeout"   // 连接超时（秒）
+	settingHandshakeTimeout = "handshake_timeout" // 握手超时（秒）
+	settingRetries          = "retries"           // 重试次数
+	settingRetryBackoff     = "retry_backoff"     // 重试等待（毫秒）
+	settingDNSServer        = "dns_server"        // CAA/TLSA查询使用的DNS服务器
+
+	settingHistoryRetentionDays = "history_retention_days" // 历史记录保留天数
+	settingHistoryMaxRows       = "history_max_rows"       // 每个目标最多保留的历史记录数
+	settingHistoryKeepChanges   = "history_keep_changes"   // 仅保留证书变化的记录
+)
+
+// Settings 探测设置（保存在 settings 表）
+type Settings struct {
+	ConnectTimeout   int    `json:"connectTimeout"`   // 连接超时（秒），经由代理时包括与代理的协商
+	HandshakeTimeout int    `json:"handshakeTimeout"` // 握手超时（秒），包括STARTTLS明文协商
+	Retries          int    `json:"retries"`          // 连接失败或超时后的重试次数
+	RetryBackoff     int    `json:"retryBackoff"`     // 首次重试前的等待（毫秒），之后每次翻倍
+	Proxy            string `json:"proxy"`            // 全局代理（为空时使用环境变量）
+	DNSServer        string `json:"dnsServer"`        // CAA/TLSA查询使用的DNS服务器（IP或IP:端口，为空时使用系统DNS配置）
+}
+
+// SettingsResult 设置查询结果
+type SettingsResult struct {
+	Success  bool     `json:"success"`
+	Message  string   `json:"message"`
+	Settings Settings `json:"settings"`
+	Defaults Settings `json:"defaults"` // 默认设置，用于恢复默认
+	Error    string   `json:"error,omitempty"`
+}
+
+// defaultSettings 返回默认探测设置
+func defaultSettings() Settings {
+	return Settings{
+		ConnectTimeout:   5,
+		HandshakeTimeout: 10,
+		Retries:          1,
+		RetryBackoff:     500,
+	}
+}
+
+// validate 校验设置取值范围并规范化代理地址
+func (s Settings) validate() (Settings, error) {
+	if s.ConnectTimeout < 1 || s.ConnectTimeout > 60 {
+		return s, fmt.Errorf("连接超时应在 1-60 秒之间")
+	}
+	if s.HandshakeTimeout < 1 || s.HandshakeTimeout > 120 {
+		return s, fmt.Errorf("握手超时应在 1-120 秒之间")
+	}
+	if s.Retries < 0 || s.Retries > 5 {
+		return s, fmt.Errorf("重试次数应在 0-5 之间")
+	}
+	if s.RetryBackoff < 0 || s.RetryBackoff > 10000 {
+		return s, fmt.Errorf("重试等待应在 0-10000 毫秒之间")
+	}
+
+	proxy, err := normalizeProxy(s.Proxy)
+	if err != nil {
+		return s, err
+	}
+	if proxy == ProxyDirect {
+		return s, fmt.Errorf("全局代理不支持 %s，留空即可", ProxyDirect)
+	}
+	s.Proxy = proxy
+
+	dnsServer, err := normalizeDNSServer(s.DNSServer)
+	if err != nil {
+		return s, err
+	}
+	s.DNSServer = dnsServer
+	return s, nil
+}
+
+// sanitize 丢弃无效的设置项：超时和重试有误时整体使用默认值，代理和DNS服务器分别校验，无效时不使用
+func (s Settings) sanitize() Settings {
+	timing := s
+	timing.Proxy, timing.DNSServer = "", ""
+	result, err := timing.validate()
+	if err != nil {
+		fmt.Printf("❌ 超时和重试使用默认设置: %v\n", err)
+		result = defaultSettings()
+	}
+
+	if proxy, err := normalizeProxy(s.Proxy); err != nil || proxy == ProxyDirect {
+		fmt.Printf("❌ 忽略无效的全局代理 %s\n", redactProxy(s.Proxy))
+	} else {
+		result.Proxy = proxy
+	}
+	if dnsServer, err := normalizeDNSServer(s.DNSServer); err != nil {
+		fmt.Printf("❌ 忽略无效的DNS服务器: %v\n", err)
+	} else {
+		result.DNSServer = dnsServer
+	}
+	return result
+}
+
+// connectTimeout、handshakeTimeout 返回超时时长
+func (s Settings) connectTimeout() time.Duration {
+	return time.Duration(s.ConnectTimeout) * time.Second
+}
+
+func (s Settings) handshakeTimeout() time.Duration {
+	return time.Duration(s.HandshakeTimeout) * time.Second
+}
+
+// retryDelay 返回第 attempt 次重试（从1开始）前的等待时间
+func (s Settings) retryDelay(attempt int) time.Duration {
+	return time.Duration(s.RetryBackoff) * time.Millisecond << (attempt - 1)
+}
+
+// currentSettings 返回当前生效的设置，尚未从数据库加载时使用默认设置
+func (a *App) currentSettings() Settings {
+	a.settingsMu.RLock()
+	defer a.settingsMu.RUnlock()
+	if !a.settingsLoaded {
+		return defaultSettings()
+	}
+	return a.settings
+}
+
+// loadSettings 从设置表读取设置，缺失或无效的项使用默认值
+func (a *App) loadSettings() {
+	settings := defaultSettings()
+	for key, field := range map[string]*int{
+		settingConnectTimeout:   &settings.ConnectTimeout,
+		settingHandshakeTimeout: &settings.HandshakeTimeout,
+		settingRetries:          &settings.Retries,
+		settingRetryBackoff:     &settings.RetryBackoff,
+	} {
+		value, err := a.getSetting(key)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil {
+			*field = n
+		}
+	}
+
+	proxy, err := a.getSetting(settingProxy)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+	if proxy, err = openProxy(proxy); err != nil {
+		fmt.Printf("❌ %v，忽略全局代理\n", err)
+	}
+	settings.Proxy = proxy
+
+	dnsServer, err := a.getSetting(settingDNSServer)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+	settings.DNSServer = dnsServer
+
+	if valid, err := settings.validate(); err == nil {
+		settings = valid
+	} else {
+		fmt.Printf("❌ 设置无效: %v\n", err)
+		settings = settings.sanitize()
+	}
+
+	a.settingsMu.Lock()
+	a.settings = settings
+	a.settingsLoaded = true
+	a.settingsMu.Unlock()
+}
+
+// GetSettings 获取探测设置，代理密码已隐藏
+func (a *App) GetSettings() SettingsResult {
+	settings := a.currentSettings()
+	settings.Proxy = redactProxy(settings.Proxy)
+	return SettingsResult{
+		Success:  true,
+		Message:  "查询设置成功",
+		Settings: settings,
+		Defaults: defaultSettings(),
+	}
+}
+
+// UpdateSettings 更新探测设置，对之后开始的探测生效；代理为 GetSettings 返回的隐藏了密码的地址时沿用已保存的密码
+func (a *App) UpdateSettings(settings Settings) error {
+	settings, err := settings.validate()
+	if err != nil {
+		return err
+	}
+	settings.Proxy = keepProxyPassword(settings.Proxy, a.globalProxy())
+	proxy, err := sealProxy(settings.Proxy)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		settingConnectTimeout:   strconv.Itoa(settings.ConnectTimeout),
+		settingHandshakeTimeout: strconv.Itoa(settings.HandshakeTimeout),
+		settingRetries:          strconv.Itoa(settings.Retries),
+		settingRetryBackoff:     strconv.Itoa(settings.RetryBackoff),
+		settingProxy:            proxy,
+		settingDNSServer:        settings.DNSServer,
+	}
+	if err := a.setSettings(values); err != nil {
+		return err
+	}
+
+	a.settingsMu.Lock()
+	a.settings = settings
+	a.settingsLoaded = true
+	a.settingsMu.Unlock()
+
+	fmt.Printf("✅ 更新设置成功: 连接超时 %ds，握手超时 %ds，重试 %d 次，代理 %s，DNS服务器 %s\n",
+		settings.ConnectTimeout, settings.HandshakeTimeout, settings.Retries, redactProxy(settings.Proxy), settings.DNSServer)
+	return nil
+}
+
+// getSetting 读取设置项，不存在时返回空字符串
+func (a *App) getSetting(key string) (string, error) {
+	if a.db == nil {
+		return "", nil
+	}
+
+	var value string
+	err := a.db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("读取设置 %s 失败: %v", key, err)
+	}
+	return value, nil
+}
+
+// setSettings 在一个事务中保存多个设置项，任一项失败时全部不生效
+func (a *App) setSettings(values map[string]string) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	tx, err := a.db.Begin()
+	if err != nil {
+		return fmt.Errorf("保存设置失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := saveSetting(tx, key, values[key]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("保存设置失败: %v", err)
+	}
+	return nil
+}
+
+// setSetting 保存设置项
+func (a *App) setSetting(key, value string) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	return saveSetting(a.db, key, value)
+}
+
+// execer 兼容 *sql.DB 和 *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// saveSetting 在数据库或事务中写入设置项
+func saveSetting(e execer, key, value string) error {
+	_, err := e.Exec(`
+	INSERT INTO settings (key, value, updated_time) VALUES (?, ?, datetime('now', 'localtime'))
+	ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_time = excluded.updated_time
+	`, key, value)
+	if err != nil {
+		return fmt.Errorf("保存设置 %s 失败: %v", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import "testing"
+
+func TestUpdateSettings(t *testing.T) {
+	a := newTestApp(t)
+	settings := defaultSettings()
+	settings.ConnectTimeout = 8
+	settings.Proxy = "socks5h://127.0.0.1:1080"
+	settings.DNSServer = "10.0.0.53"
+	if err := a.UpdateSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &App{db: a.db}
+	b.loadSettings()
+	got := b.currentSettings()
+	if got.ConnectTimeout != 8 || got.Proxy != settings.Proxy || got.DNSServer != "10.0.0.53:53" {
+		t.Fatalf("重新加载的设置不正确: %+v", got)
+	}
+
+	settings.Retries = 9
+	if err := a.UpdateSettings(settings); err == nil {
+		t.Fatal("超出范围的重试次数应返回错误")
+	}
+}
+
+func TestLoadSettingsDropsInvalid(t *testing.T) {
+	a := newTestApp(t)
+	if err := a.setSettings(map[string]string{
+		settingConnectTimeout: "8",
+		settingProxy:          "socks4://127.0.0.1:1080",
+		settingDNSServer:      "10.0.0.53",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	a.loadSettings()
+	got := a.currentSettings()
+	if got.ConnectTimeout != 8 || got.Proxy != "" || got.DNSServer != "10.0.0.53:53" {
+		t.Fatalf("应只丢弃无效的代理: %+v", got)
+	}
+
+	if err := a.setSettings(map[string]string{
+		settingRetries:   "9",
+		settingProxy:     "socks5://127.0.0.1:1080",
+		settingDNSServer: "not a server",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	a.loadSettings()
+	got = a.currentSettings()
+	want := defaultSettings()
+	want.Proxy = "socks5://127.0.0.1:1080"
+	if got != want {
+		t.Fatalf("重试次数无效时超时和重试应使用默认值，并丢弃无效的DNS服务器: %+v", got)
+	}
+}
+
+func TestSetSettingsAtomic(t *testing.T) {
+	a := newTestApp(t)
+	saved := map[string]string{settingConnectTimeout: "8", settingHistoryMaxRows: "100"}
+	if err := a.setSettings(saved); err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入其中一项时失败（UpdateSettings、UpdateRetentionPolicy 均经由 setSettings 保存）
+	_, err := a.db.Exec(`
+	CREATE TRIGGER fail_retries BEFORE INSERT ON settings WHEN NEW.key = 'retries'
+	BEGIN SELECT RAISE(ABORT, 'disk full'); END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = a.setSettings(map[string]string{
+		settingConnectTimeout: "20",
+		settingHistoryMaxRows: "5",
+		settingRetries:        "3",
+		settingDNSServer:      "10.0.0.53:53",
+	})
+	if err == nil {
+		t.Fatal("写入失败时应返回错误")
+	}
+
+	rows, err := a.db.Query("SELECT key, value FROM settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	got := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			t.Fatal(err)
+		}
+		got[key] = value
+	}
+	if len(got) != len(saved) || got[settingConnectTimeout] != "8" || got[settingHistoryMaxRows] != "100" {
+		t.Fatalf("写入失败后设置应保持不变，实际为 %v", got)
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// STARTTLS 协议类型（空字符串表示直接TLS）
+const (
+	ProtocolTLS      = ""
+	ProtocolSMTP     = "smtp"
+	ProtocolIMAP     = "imap"
+	ProtocolPOP3     = "pop3"
+	ProtocolFTP      = "ftp"
+	ProtocolLDAP     = "ldap"
+	ProtocolXMPP     = "xmpp"
+	ProtocolPostgres = "postgres"
+)
+
+// protocolDefaultPorts 各协议的默认端口
+var protocolDefaultPorts = map[string]int{
+	ProtocolTLS:      defaultTLSPort,
+	ProtocolSMTP:     25,
+	ProtocolIMAP:     143,
+	ProtocolPOP3:     110,
+	ProtocolFTP:      21,
+	ProtocolLDAP:     389,
+	ProtocolXMPP:     5222,
+	ProtocolPostgres: 5432,
+}
+
+// protocolAliases URL协议前缀到协议类型的映射
+var protocolAliases = map[string]string{
+	"":           ProtocolTLS,
+	"tls":        ProtocolTLS,
+	"https":      ProtocolTLS,
+	"http":       ProtocolTLS,
+	"smtp":       ProtocolSMTP,
+	"imap":       ProtocolIMAP,
+	"pop3":       ProtocolPOP3,
+	"pop":        ProtocolPOP3,
+	"ftp":        ProtocolFTP,
+	"ldap":       ProtocolLDAP,
+	"xmpp":       ProtocolXMPP,
+	"postgres":   ProtocolPostgres,
+	"postgresql": ProtocolPostgres,
+}
+
+// normalizeProtocol 规范化协议名称
+func normalizeProtocol(protocol string) (string, error) {
+	p, ok := protocolAliases[strings.ToLower(strings.TrimSpace(protocol))]
+	if !ok {
+		return "", fmt.Errorf("不支持的协议: %s", protocol)
+	}
+	return p, nil
+}
+
+// startTLS 在明文连接上执行对应协议的STARTTLS握手，成功后即可在该连接上开始TLS握手
+func startTLS(conn net.Conn, protocol, host string) error {
+	switch protocol {
+	case ProtocolTLS:
+		return nil
+	case ProtocolSMTP:
+		return startTLSSMTP(conn)
+	case ProtocolIMAP:
+		return startTLSIMAP(conn)
+	case ProtocolPOP3:
+		return startTLSPOP3(conn)
+	case ProtocolFTP:
+		return startTLSFTP(conn)
+	case ProtocolLDAP:
+		return startTLSLDAP(conn)
+	case ProtocolXMPP:
+		return startTLSXMPP(conn, host)
+	case ProtocolPostgres:
+		return startTLSPostgres(conn)
+	default:
+		return fmt.Errorf("不支持的协议: %s", protocol)
+	}
+}
+
+// readReplyCode 读取 SMTP/FTP 风格的多行响应（"250-..." 续行，"250 ..." 结束），返回响应码和全部内容
+func readReplyCode(r *bufio.Reader) (string, string, error) {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+		if len(line) < 3 {
+			return "", "", fmt.Errorf("无效的服务器响应: %q", line)
+		}
+		if len(line) == 3 || line[3] != '-' {
+			return line[:3], strings.Join(lines, "\n"), nil
+		}
+	}
+}
+
+// expectReply 读取响应并校验响应码
+func expectReply(r *bufio.Reader, code string) (string, error) {
+	got, text, err := readReplyCode(r)
+	if err != nil {
+		return "", err
+	}
+	if got != code {
+		return "", fmt.Errorf("服务器返回 %s，期望 %s: %s", got, code, text)
+	}
+	return text, nil
+}
+
+// ensureDrained 确认STARTTLS响应之后没有多余的明文数据（防止明文命令注入）
+func ensureDrained(r *bufio.Reader) error {
+	if r.Buffered() > 0 {
+		return fmt.Errorf("STARTTLS响应后存在多余的明文数据")
+	}
+	return nil
+}
+
+// startTLSSMTP SMTP: 220 问候 -> EHLO -> STARTTLS -> 220
+func startTLSSMTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	if _, err := expectReply(r, "220"); err != nil {
+		return fmt.Errorf("SMTP问候失败: %v", err)
+	}
+
+	if _, err := io.WriteString(conn, "EHLO ssl-cert-checker\r\n"); err != nil {
+		return err
+	}
+	caps, err := expectReply(r, "250")
+	if err != nil {
+		return fmt.Errorf("SMTP EHLO失败: %v", err)
+	}
+	if !strings.Contains(strings.ToUpper(caps), "STARTTLS") {
+		return fmt.Errorf("SMTP服务器不支持STARTTLS")
+	}
+
+	if _, err := io.WriteString(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	if _, err := expectReply(r, "220"); err != nil {
+		return fmt.Errorf("SMTP STARTTLS失败: %v", err)
+	}
+	return ensureDrained(r)
+}
+
+// startTLSIMAP IMAP: * OK 问候 -> a001 STARTTLS -> a001 OK
+func startTLSIMAP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	greeting, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("IMAP问候失败: %v", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("IMAP问候异常: %q", strings.TrimSpace(greeting))
+	}
+
+	if _, err := io.WriteString(conn, "a001 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("IMAP STARTTLS失败: %v", err)
+		}
+		// 忽略未标记的响应（如 * CAPABILITY）
+		if strings.HasPrefix(line, "*") {
+			continue
+		}
+		if strings.HasPrefix(line, "a001 OK") {
+			return ensureDrained(r)
+		}
+		return fmt.Errorf("IMAP STARTTLS被拒绝: %q", strings.TrimSpace(line))
+	}
+}
+
+// startTLSPOP3 POP3: +OK 问候 -> STLS -> +OK
+func startTLSPOP3(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	greeting, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("POP3问候失败: %v", err)
+	}
+	if !strings.HasPrefix(greeting, "+OK") {
+		return fmt.Errorf("POP3问候异常: %q", strings.TrimSpace(greeting))
+	}
+
+	if _, err := io.WriteString(conn, "STLS\r\n"); err != nil {
+		return err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("POP3 STLS失败: %v", err)
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("POP3 STLS被拒绝: %q", strings.TrimSpace(line))
+	}
+	return ensureDrained(r)
+}
+
+// startTLSFTP FTP: 220 问候 -> AUTH TLS -> 234
+func startTLSFTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	if _, err := expectReply(r, "220"); err != nil {
+		return fmt.Errorf("FTP问候失败: %v", err)
+	}
+
+	if _, err := io.WriteString(conn, "AUTH TLS\r\n"); err != nil {
+		return err
+	}
+	if _, err := expectReply(r, "234"); err != nil {
+		return fmt.Errorf("FTP AUTH TLS失败: %v", err)
+	}
+	return ensureDrained(r)
+}
+
+// ldapStartTLSOID LDAP StartTLS扩展操作OID（RFC 4511）
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// startTLSLDAP LDAP: 发送 StartTLS ExtendedRequest，校验 ExtendedResponse 的 resultCode
+func startTLSLDAP(conn net.Conn) error {
+	// LDAPMessage ::= SEQUENCE { messageID 1, [APPLICATION 23] { [0] requestName } }
+	oid := []byte(ldapStartTLSOID)
+	request := append([]byte{0x77, byte(len(oid) + 2), 0x80, byte(len(oid))}, oid...)
+	request = append([]byte{0x02, 0x01, 0x01}, request...)
+	request = append([]byte{0x30, byte(len(request))}, request...)
+
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	message, err := readBERElement(r)
+	if err != nil {
+		return fmt.Errorf("读取LDAP响应失败: %v", err)
+	}
+
+	var envelope asn1.RawValue
+	if _, err := asn1.Unmarshal(message, &envelope); err != nil {
+		return fmt.Errorf("解析LDAP响应失败: %v", err)
+	}
+
+	// 依次解析 messageID 和 ExtendedResponse
+	var messageID int
+	rest, err := asn1.Unmarshal(envelope.Bytes, &messageID)
+	if err != nil {
+		return fmt.Errorf("解析LDAP响应失败: %v", err)
+	}
+	var response asn1.RawValue
+	if _, err := asn1.Unmarshal(rest, &response); err != nil {
+		return fmt.Errorf("解析LDAP响应失败: %v", err)
+	}
+	if response.Class != asn1.ClassApplication || response.Tag != 24 {
+		return fmt.Errorf("LDAP响应类型异常: %d", response.Tag)
+	}
+
+	var resultCode asn1.Enumerated
+	if _, err := asn1.Unmarshal(response.Bytes, &resultCode); err != nil {
+		return fmt.Errorf("解析LDAP resultCode失败: %v", err)
+	}
+	if resultCode != 0 {
+		return fmt.Errorf("LDAP StartTLS被拒绝，resultCode=%d", resultCode)
+	}
+	return ensureDrained(r)
+}
+
+// readBERElement 从流中读取一个完整的BER元素（tag + length + content）
+func readBERElement(r *bufio.Reader) ([]byte, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	header := []byte{tag, first}
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, fmt.Errorf("不支持的BER长度编码")
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			header = append(header, b)
+			length = length<<8 | int(b)
+		}
+	}
+	if length > 1<<20 {
+		return nil, fmt.Errorf("BER元素过大: %d", length)
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	return append(header, content...), nil
+}
+
+// xmppMaxRead XMPP协商阶段最多读取的字节数
+const xmppMaxRead = 64 * 1024
+
+// startTLSXMPP XMPP: 打开流 -> 等待 stream:features 中的 starttls -> 发送 starttls -> 等待 proceed
+func startTLSXMPP(conn net.Conn, host string) error {
+	header := fmt.Sprintf("<?xml version='1.0'?><stream:stream to='%s' xmlns='jabber:client' "+
+		"xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>", host)
+	if _, err := io.WriteString(conn, header); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	features, err := readUntil(r, "</stream:features>")
+	if err != nil {
+		return fmt.Errorf("读取XMPP stream:features失败: %v", err)
+	}
+	if !strings.Contains(features, "urn:ietf:params:xml:ns:xmpp-tls") {
+		return fmt.Errorf("XMPP服务器不支持STARTTLS")
+	}
+
+	if _, err := io.WriteString(conn, "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"); err != nil {
+		return err
+	}
+	reply, err := readUntil(r, "/>")
+	if err != nil {
+		return fmt.Errorf("XMPP STARTTLS失败: %v", err)
+	}
+	if !strings.Contains(reply, "<proceed") {
+		return fmt.Errorf("XMPP STARTTLS被拒绝: %s", strings.TrimSpace(reply))
+	}
+	return ensureDrained(r)
+}
+
+// readUntil 持续读取直到出现指定标记
+func readUntil(r *bufio.Reader, marker string) (string, error) {
+	var sb strings.Builder
+	for sb.Len() < xmppMaxRead {
+		b, err := r.ReadByte()
+		if err != nil {
+			return sb.String(), err
+		}
+		sb.WriteByte(b)
+		if strings.HasSuffix(sb.String(), marker) {
+			return sb.String(), nil
+		}
+	}
+	return sb.String(), fmt.Errorf("响应过长")
+}
+
+// postgresSSLRequestCode PostgreSQL SSLRequest 魔数
+const postgresSSLRequestCode = 80877103
+
+// startTLSPostgres PostgreSQL: 发送 SSLRequest，服务器返回 'S' 表示接受
+func startTLSPostgres(conn net.Conn) error {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("读取PostgreSQL响应失败: %v", err)
+	}
+	if reply[0] != 'S' {
+		return fmt.Errorf("PostgreSQL服务器未启用SSL")
+	}
+	return nil
+}
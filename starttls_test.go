@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pipeStep 模拟服务器的一步：先读取客户端应发送的内容，再写入响应
+type pipeStep struct {
+	client string
+	server string
+}
+
+// runPipeServer 在 net.Pipe 的服务端按步骤执行对话，返回客户端连接和服务端的校验结果
+func runPipeServer(t *testing.T, steps []pipeStep) (net.Conn, <-chan error) {
+	t.Helper()
+	client, server := net.Pipe()
+	deadline := time.Now().Add(5 * time.Second)
+	client.SetDeadline(deadline)
+	server.SetDeadline(deadline)
+	t.Cleanup(func() { client.Close() })
+
+	done := make(chan error, 1)
+	go func() {
+		defer server.Close()
+		for _, step := range steps {
+			if step.client != "" {
+				buf := make([]byte, len(step.client))
+				if _, err := io.ReadFull(server, buf); err != nil {
+					done <- fmt.Errorf("读取客户端请求失败: %v", err)
+					return
+				}
+				if string(buf) != step.client {
+					done <- fmt.Errorf("客户端发送 %q，期望 %q", buf, step.client)
+					return
+				}
+			}
+			if step.server != "" {
+				if _, err := io.WriteString(server, step.server); err != nil {
+					done <- fmt.Errorf("写入响应失败: %v", err)
+					return
+				}
+			}
+		}
+		done <- nil
+	}()
+	return client, done
+}
+
+// ldapStartTLSRequest 客户端应发送的 StartTLS ExtendedRequest
+const ldapStartTLSRequest = "\x30\x1d\x02\x01\x01\x77\x18\x80\x16" + ldapStartTLSOID
+
+// ldapResponse 构造 ExtendedResponse（messageID 1，matchedDN 和 diagnosticMessage 为空）
+func ldapResponse(resultCode byte) string {
+	return "\x30\x0c\x02\x01\x01\x78\x07\x0a\x01" + string([]byte{resultCode}) + "\x04\x00\x04\x00"
+}
+
+func TestStartTLS(t *testing.T) {
+	xmppHeader := fmt.Sprintf("<?xml version='1.0'?><stream:stream to='%s' xmlns='jabber:client' "+
+		"xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>", "chat.example.com")
+	xmppFeatures := "<?xml version='1.0'?><stream:stream from='chat.example.com' version='1.0'>" +
+		"<stream:features><starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'><required/></starttls></stream:features>"
+	xmppStartTLS := "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"
+	postgresRequest := "\x00\x00\x00\x08\x04\xd2\x16\x2f"
+
+	tests := []struct {
+		name     string
+		protocol string
+		steps    []pipeStep
+		wantErr  string
+	}{
+		{"SMTP", ProtocolSMTP, []pipeStep{
+			{"", "220 mail.example.com ESMTP\r\n"},
+			{"EHLO ssl-cert-checker\r\n", "250-mail.example.com\r\n250-SIZE 35882577\r\n250 STARTTLS\r\n"},
+			{"STARTTLS\r\n", "220 2.0.0 Ready to start TLS\r\n"},
+		}, ""},
+		{"SMTP 不支持STARTTLS", ProtocolSMTP, []pipeStep{
+			{"", "220 mail.example.com ESMTP\r\n"},
+			{"EHLO ssl-cert-checker\r\n", "250-mail.example.com\r\n250 8BITMIME\r\n"},
+		}, "不支持STARTTLS"},
+		{"SMTP 问候被拒绝", ProtocolSMTP, []pipeStep{
+			{"", "554 no service\r\n"},
+		}, "服务器返回 554"},
+		{"SMTP 响应后的明文注入", ProtocolSMTP, []pipeStep{
+			{"", "220 mail.example.com ESMTP\r\n"},
+			{"EHLO ssl-cert-checker\r\n", "250 STARTTLS\r\n"},
+			{"STARTTLS\r\n", "220 Ready\r\nMAIL FROM:<evil@example.com>\r\n"},
+		}, "多余的明文数据"},
+		{"IMAP", ProtocolIMAP, []pipeStep{
+			{"", "* OK IMAP4rev1 ready\r\n"},
+			{"a001 STARTTLS\r\n", "* CAPABILITY IMAP4rev1\r\na001 OK Begin TLS negotiation now\r\n"},
+		}, ""},
+		{"IMAP 被拒绝", ProtocolIMAP, []pipeStep{
+			{"", "* OK IMAP4rev1 ready\r\n"},
+			{"a001 STARTTLS\r\n", "a001 BAD STARTTLS not available\r\n"},
+		}, "IMAP STARTTLS被拒绝"},
+		{"IMAP 问候异常", ProtocolIMAP, []pipeStep{
+			{"", "* BYE going away\r\n"},
+		}, "IMAP问候异常"},
+		{"POP3", ProtocolPOP3, []pipeStep{
+			{"", "+OK POP3 ready\r\n"},
+			{"STLS\r\n", "+OK Begin TLS\r\n"},
+		}, ""},
+		{"POP3 被拒绝", ProtocolPOP3, []pipeStep{
+			{"", "+OK POP3 ready\r\n"},
+			{"STLS\r\n", "-ERR command not supported\r\n"},
+		}, "POP3 STLS被拒绝"},
+		{"FTP", ProtocolFTP, []pipeStep{
+			{"", "220-Welcome\r\n220 FTP ready\r\n"},
+			{"AUTH TLS\r\n", "234 AUTH TLS successful\r\n"},
+		}, ""},
+		{"FTP 被拒绝", ProtocolFTP, []pipeStep{
+			{"", "220 FTP ready\r\n"},
+			{"AUTH TLS\r\n", "530 Please login first\r\n"},
+		}, "服务器返回 530"},
+		{"LDAP", ProtocolLDAP, []pipeStep{
+			{ldapStartTLSRequest, ldapResponse(0)},
+		}, ""},
+		{"LDAP 被拒绝", ProtocolLDAP, []pipeStep{
+			{ldapStartTLSRequest, ldapResponse(2)},
+		}, "resultCode=2"},
+		{"LDAP 响应类型异常", ProtocolLDAP, []pipeStep{
+			{ldapStartTLSRequest, "\x30\x08\x02\x01\x01\x65\x03\x0a\x01\x00"},
+		}, "响应类型异常"},
+		{"XMPP", ProtocolXMPP, []pipeStep{
+			{xmppHeader, xmppFeatures},
+			{xmppStartTLS, "<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"},
+		}, ""},
+		{"XMPP 不支持STARTTLS", ProtocolXMPP, []pipeStep{
+			{xmppHeader, "<stream:stream><stream:features><bind/></stream:features>"},
+		}, "不支持STARTTLS"},
+		{"XMPP 被拒绝", ProtocolXMPP, []pipeStep{
+			{xmppHeader, xmppFeatures},
+			{xmppStartTLS, "<failure xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"},
+		}, "XMPP STARTTLS被拒绝"},
+		{"PostgreSQL", ProtocolPostgres, []pipeStep{
+			{postgresRequest, "S"},
+		}, ""},
+		{"PostgreSQL 未启用SSL", ProtocolPostgres, []pipeStep{
+			{postgresRequest, "N"},
+		}, "未启用SSL"},
+		{"连接提前关闭", ProtocolPOP3, nil, "POP3问候失败"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, done := runPipeServer(t, tt.steps)
+			err := startTLS(conn, tt.protocol, "chat.example.com")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("startTLS 失败: %v", err)
+				}
+				if serverErr := <-done; serverErr != nil {
+					t.Fatal(serverErr)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("startTLS 错误为 %v，期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeProtocol(t *testing.T) {
+	for input, want := range map[string]string{"": ProtocolTLS, "HTTPS": ProtocolTLS, " pop ": ProtocolPOP3, "postgresql": ProtocolPostgres} {
+		if got, err := normalizeProtocol(input); err != nil || got != want {
+			t.Errorf("normalizeProtocol(%q) = %q, %v，期望 %q", input, got, err, want)
+		}
+	}
+	if _, err := normalizeProtocol("gopher"); err == nil {
+		t.Error("不支持的协议应返回错误")
+	}
+}
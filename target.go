@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultTLSPort 默认HTTPS端口
+const defaultTLSPort = 443
+
+// 域名长度限制（RFC 1035）
+const (
+	maxHostLength  = 253
+	maxLabelLength = 63
+)
+
+// Target 探测目标（主机 + 端口 + 协议）
+type Target struct {
+	Host     string
+	Port     int
+	Protocol string // STARTTLS协议，空字符串表示直接TLS
+}
+
+// Address 返回用于拨号的 host:port 地址（IPv6 自动加方括号）
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// String 返回展示用的目标字符串，默认端口时省略端口，STARTTLS协议时附加协议前缀
+func (t Target) String() string {
+	address := t.Address()
+	if t.Port == protocolDefaultPorts[t.Protocol] {
+		address = t.Host
+		if strings.Contains(t.Host, ":") {
+			address = "[" + t.Host + "]"
+		}
+	}
+	if t.Protocol == ProtocolTLS {
+		return address
+	}
+	return t.Protocol + "://" + address
+}
+
+// parseTarget 解析用户输入的目标
+// 支持以下格式：
+//   - example.com
+//   - example.com:8443
+//   - https://example.com:8443/path
+//   - smtp://mail.example.com（STARTTLS，端口默认为协议端口）
+//   - [2001:db8::1]:8443
+//   - 2001:db8::1
+//
+// 主机名统一转换为小写、去掉末尾的点，国际化域名转换为punycode（如 中文.com → xn--fiq228c.com）
+func parseTarget(input string) (Target, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return Target{}, fmt.Errorf("域名不能为空")
+	}
+
+	// 带协议前缀的URL格式
+	if strings.Contains(input, "://") {
+		u, err := url.Parse(input)
+		if err != nil {
+			return Target{}, fmt.Errorf("无法解析地址 %s: %v", input, err)
+		}
+		protocol, err := normalizeProtocol(u.Scheme)
+		if err != nil {
+			return Target{}, err
+		}
+		return newTarget(u.Hostname(), u.Port(), protocol)
+	}
+
+	// 去掉路径、查询参数部分
+	if i := strings.IndexAny(input, "/?#"); i >= 0 {
+		input = input[:i]
+	}
+
+	// [IPv6]:port 或 [IPv6]
+	if strings.HasPrefix(input, "[") {
+		if strings.HasSuffix(input, "]") {
+			return newTarget(strings.Trim(input, "[]"), "", ProtocolTLS)
+		}
+		host, port, err := net.SplitHostPort(input)
+		if err != nil {
+			return Target{}, fmt.Errorf("无法解析地址 %s: %v", input, err)
+		}
+		return newTarget(host, port, ProtocolTLS)
+	}
+
+	switch strings.Count(input, ":") {
+	case 0:
+		return newTarget(input, "", ProtocolTLS)
+	case 1:
+		host, port, err := net.SplitHostPort(input)
+		if err != nil {
+			return Target{}, fmt.Errorf("无法解析地址 %s: %v", input, err)
+		}
+		return newTarget(host, port, ProtocolTLS)
+	default:
+		// 不带方括号的IPv6地址，不支持附带端口
+		if net.ParseIP(input) == nil {
+			return Target{}, fmt.Errorf("无效的IPv6地址: %s（带端口时请使用 [IPv6]:端口 格式）", input)
+		}
+		return newTarget(input, "", ProtocolTLS)
+	}
+}
+
+// newTarget 校验主机和端口并构造目标，未指定端口时使用协议默认端口
+func newTarget(host, port, protocol string) (Target, error) {
+	host, err := normalizeHost(host)
+	if err != nil {
+		return Target{}, err
+	}
+
+	target := Target{Host: host, Port: protocolDefaultPorts[protocol], Protocol: protocol}
+	if port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil || p < 1 || p > 65535 {
+			return Target{}, fmt.Errorf("无效的端口: %s", port)
+		}
+		target.Port = p
+	}
+
+	return target, nil
+}
+
+// normalizeHost 规范化并校验主机名：IP地址转换为标准形式，域名经IDNA转换为小写ASCII形式
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.TrimSpace(host), ".")
+	if host == "" {
+		return "", fmt.Errorf("域名不能为空")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	if strings.HasPrefix(host, "*.") {
+		return "", fmt.Errorf("无法直接查询通配符域名 %s，请输入具体的主机名，如 www.%s", host, host[2:])
+	}
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		reason := strings.TrimPrefix(err.Error(), "idna: ")
+		reason = strings.Replace(reason, "disallowed rune", "包含不允许的字符", 1)
+		return "", fmt.Errorf("无效的域名 %s: %s", host, reason)
+	}
+	// 全角句号等在映射后才成为点
+	ascii = strings.TrimSuffix(ascii, ".")
+	if len(ascii) > maxHostLength {
+		return "", fmt.Errorf("无效的域名 %s: 长度超过 %d 个字符", host, maxHostLength)
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if label == "" {
+			return "", fmt.Errorf("无效的域名 %s: 包含空的标签", host)
+		}
+		if len(label) > maxLabelLength {
+			return "", fmt.Errorf("无效的域名 %s: 标签 %s 超过 %d 个字符", host, label, maxLabelLength)
+		}
+	}
+	return ascii, nil
+}
+
+// unicodeHost 返回punycode域名的Unicode形式，非国际化域名返回空字符串
+func unicodeHost(host string) string {
+	if !strings.Contains(host, "xn--") {
+		return ""
+	}
+	display, err := idna.Display.ToUnicode(host)
+	if err != nil || display == host {
+		return ""
+	}
+	return display
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Target
+		wantErr string
+	}{
+		{"example.com", Target{Host: "example.com", Port: 443}, ""},
+		{"  Example.COM.  ", Target{Host: "example.com", Port: 443}, ""},
+		{"example.com:8443", Target{Host: "example.com", Port: 8443}, ""},
+		{"example.com/path?q=1", Target{Host: "example.com", Port: 443}, ""},
+		{"https://example.com:8443/path", Target{Host: "example.com", Port: 8443}, ""},
+		{"http://example.com", Target{Host: "example.com", Port: 443}, ""},
+		{"smtp://mail.example.com", Target{Host: "mail.example.com", Port: 25, Protocol: ProtocolSMTP}, ""},
+		{"postgresql://db.example.com:6432", Target{Host: "db.example.com", Port: 6432, Protocol: ProtocolPostgres}, ""},
+		{"[2001:db8::1]:8443", Target{Host: "2001:db8::1", Port: 8443}, ""},
+		{"[2001:DB8::1]", Target{Host: "2001:db8::1", Port: 443}, ""},
+		{"2001:db8::1", Target{Host: "2001:db8::1", Port: 443}, ""},
+		{"192.0.2.1:993", Target{Host: "192.0.2.1", Port: 993}, ""},
+		{"中文.com", Target{Host: "xn--fiq228c.com", Port: 443}, ""},
+		{"", Target{}, "域名不能为空"},
+		{"example.com:0", Target{}, "无效的端口"},
+		{"example.com:http", Target{}, "无效的端口"},
+		{"gopher://example.com", Target{}, "不支持的协议"},
+		{"2001:db8::1:zz", Target{}, "无效的IPv6地址"},
+		{"*.example.com", Target{}, "通配符"},
+	}
+	for _, tt := range tests {
+		got, err := parseTarget(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseTarget(%q) 错误为 %v，期望包含 %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseTarget(%q) = %+v, %v，期望 %+v", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	long := strings.Repeat("a", 64)
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"WWW.Example.com.", "www.example.com", ""},
+		{"bücher.example", "xn--bcher-kva.example", ""},
+		{"例子。测试", "xn--fsqu00a.xn--0zwm56d", ""},
+		{"::ffff:192.0.2.1", "192.0.2.1", ""},
+		{" ", "", "域名不能为空"},
+		{"*.example.com", "", "通配符"},
+		{"exa mple.com", "", "无效的域名"},
+		{"a..example.com", "", "空的标签"},
+		{long + ".com", "", "超过 63 个字符"},
+		{strings.Repeat("abcdefghi.", 26) + "com", "", "长度超过 253 个字符"},
+	}
+	for _, tt := range tests {
+		got, err := normalizeHost(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("normalizeHost(%q) 错误为 %v，期望包含 %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeHost(%q) = %q, %v，期望 %q", tt.input, got, err, tt.want)
+		}
+	}
+
+	if display := unicodeHost("xn--fiq228c.com"); display != "中文.com" {
+		t.Errorf("unicodeHost 返回 %q，期望 中文.com", display)
+	}
+}
+
+func TestTargetString(t *testing.T) {
+	tests := []struct {
+		target Target
+		want   string
+	}{
+		{Target{Host: "example.com", Port: 443}, "example.com"},
+		{Target{Host: "example.com", Port: 8443}, "example.com:8443"},
+		{Target{Host: "2001:db8::1", Port: 8443}, "[2001:db8::1]:8443"},
+		{Target{Host: "mail.example.com", Port: 25, Protocol: ProtocolSMTP}, "smtp://mail.example.com"},
+		{Target{Host: "mail.example.com", Port: 587, Protocol: ProtocolSMTP}, "smtp://mail.example.com:587"},
+	}
+	for _, tt := range tests {
+		if got := tt.target.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q，期望 %q", tt.target, got, tt.want)
+		}
+	}
+}
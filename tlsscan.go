@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TLS配置评级
+const (
+	GradeA = "A" // 仅支持TLS 1.2及以上，加密套件均具备前向保密且不含弱套件
+	GradeB = "B" // 支持TLS 1.0/1.1，或接受不具备前向保密的加密套件
+	GradeC = "C" // 接受弱加密套件（RC4、3DES、CBC-SHA256等）
+	GradeF = "F" // 不支持TLS 1.2及以上版本
+)
+
+// scanConcurrency 深度扫描时同时进行的握手数量
+const scanConcurrency = 8
+
+// scanVersions 深度扫描探测的协议版本（从低到高）
+var scanVersions = []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13}
+
+// scanCurves 深度扫描探测的密钥交换曲线
+var scanCurves = []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384, tls.CurveP521}
+
+// CipherSuiteResult 服务器接受的加密套件
+type CipherSuiteResult struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`        // 协议版本，如 "TLS 1.2"
+	Insecure       bool   `json:"insecure"`       // 是否为弱加密套件
+	ForwardSecrecy bool   `json:"forwardSecrecy"` // 是否具备前向保密
+}
+
+// TLSScanResult 深度扫描结果
+type TLSScanResult struct {
+	SupportedVersions []string            `json:"supportedVersions"` // 支持的协议版本
+	NegotiatedVersion string              `json:"negotiatedVersion"` // 默认配置下协商的协议版本
+	NegotiatedCipher  string              `json:"negotiatedCipher"`  // 默认配置下协商的加密套件
+	ALPN              string              `json:"alpn,omitempty"`    // 协商的应用层协议（提供 h2、http/1.1）
+	SupportedCurves   []string            `json:"supportedCurves"`   // 支持的密钥交换曲线
+	CipherSuites      []CipherSuiteResult `json:"cipherSuites"`      // 接受的加密套件
+	Grade             string              `json:"grade"`             // 评级 A/B/C/F
+	Issues            []string            `json:"issues,omitempty"`  // 发现的问题
+	Error             string              `json:"error,omitempty"`   // 扫描失败原因
+}
+
+// scanTLS 对目标进行深度扫描：用受限的协议版本和加密套件反复握手，
+// 记录支持的协议版本、加密套件、ALPN和曲线，并给出评级
+func (a *App) scanTLS(ctx context.Context, target Target, address string, opts ProbeOptions) *TLSScanResult {
+	result := &TLSScanResult{}
+
+	// 默认配置握手，记录协商结果
+	state, _, err := a.handshakeWith(ctx, target, address, opts, func(c *tls.Config) {
+		c.NextProtos = []string{"h2", "http/1.1"}
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("握手失败: %v", err)
+		return result
+	}
+	result.NegotiatedVersion = tls.VersionName(state.Version)
+	result.NegotiatedCipher = tls.CipherSuiteName(state.CipherSuite)
+	result.ALPN = state.NegotiatedProtocol
+
+	// 逐个协议版本握手
+	versionSupported := make([]bool, len(scanVersions))
+	a.runScan(len(scanVersions), func(i int) {
+		_, _, err := a.handshakeWith(ctx, target, address, opts, func(c *tls.Config) {
+			c.MinVersion = scanVersions[i]
+			c.MaxVersion = scanVersions[i]
+			c.CipherSuites = allCipherSuiteIDs()
+		})
+		versionSupported[i] = err == nil
+	})
+
+	// TLS 1.0-1.2 逐个加密套件握手（TLS 1.3 的加密套件不可配置，记录协商结果）
+	type cipherProbe struct {
+		version uint16
+		suite   *tls.CipherSuite
+	}
+	var probes []cipherProbe
+	maxVersion := uint16(0)
+	for i, version := range scanVersions {
+		if !versionSupported[i] {
+			continue
+		}
+		result.SupportedVersions = append(result.SupportedVersions, tls.VersionName(version))
+		maxVersion = version
+		if version == tls.VersionTLS13 {
+			continue
+		}
+		for _, suite := range allCipherSuites() {
+			if supportsVersion(suite, version) {
+				probes = append(probes, cipherProbe{version, suite})
+			}
+		}
+	}
+
+	accepted := make([]bool, len(probes))
+	a.runScan(len(probes), func(i int) {
+		_, _, err := a.handshakeWith(ctx, target, address, opts, func(c *tls.Config) {
+			c.MinVersion = probes[i].version
+			c.MaxVersion = probes[i].version
+			c.CipherSuites = []uint16{probes[i].suite.ID}
+		})
+		accepted[i] = err == nil
+	})
+
+	if maxVersion == tls.VersionTLS13 {
+		tls13, _, err := a.handshakeWith(ctx, target, address, opts, func(c *tls.Config) {
+			c.MinVersion = tls.VersionTLS13
+		})
+		if err == nil {
+			result.CipherSuites = append(result.CipherSuites, CipherSuiteResult{
+				Name:           tls.CipherSuiteName(tls13.CipherSuite),
+				Version:        tls.VersionName(tls.VersionTLS13),
+				ForwardSecrecy: true,
+			})
+		}
+	}
+	for i, probe := range probes {
+		if !accepted[i] {
+			continue
+		}
+		result.CipherSuites = append(result.CipherSuites, CipherSuiteResult{
+			Name:           probe.suite.Name,
+			Version:        tls.VersionName(probe.version),
+			Insecure:       probe.suite.Insecure,
+			ForwardSecrecy: strings.HasPrefix(probe.suite.Name, "TLS_ECDHE_"),
+		})
+	}
+
+	// 逐个曲线握手（使用支持的最高协议版本）
+	curveSupported := make([]bool, len(scanCurves))
+	a.runScan(len(scanCurves), func(i int) {
+		_, _, err := a.handshakeWith(ctx, target, address, opts, func(c *tls.Config) {
+			c.MinVersion = tls.VersionTLS10
+			c.CipherSuites = allCipherSuiteIDs()
+			c.CurvePreferences = []tls.CurveID{scanCurves[i]}
+		})
+		curveSupported[i] = err == nil
+	})
+	for i, curve := range scanCurves {
+		if curveSupported[i] {
+			result.SupportedCurves = append(result.SupportedCurves, curve.String())
+		}
+	}
+
+	result.Grade, result.Issues = gradeTLS(result)
+	return result
+}
+
+// runScan 以有限并发执行 n 次扫描握手
+func (a *App) runScan(n int, probe func(i int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, scanConcurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			probe(index)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// allCipherSuites 返回Go支持的全部TLS 1.0-1.2加密套件（含弱套件）
+func allCipherSuites() []*tls.CipherSuite {
+	var suites []*tls.CipherSuite
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if supportsVersion(suite, tls.VersionTLS10) || supportsVersion(suite, tls.VersionTLS11) ||
+			supportsVersion(suite, tls.VersionTLS12) {
+			suites = append(suites, suite)
+		}
+	}
+	return suites
+}
+
+// allCipherSuiteIDs 返回 allCipherSuites 的ID列表
+func allCipherSuiteIDs() []uint16 {
+	var ids []uint16
+	for _, suite := range allCipherSuites() {
+		ids = append(ids, suite.ID)
+	}
+	return ids
+}
+
+// supportsVersion 判断加密套件是否可用于指定协议版本
+func supportsVersion(suite *tls.CipherSuite, version uint16) bool {
+	for _, v := range suite.SupportedVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// gradeTLS 根据扫描结果评级，返回评级和问题列表
+func gradeTLS(result *TLSScanResult) (string, []string) {
+	var issues []string
+	supports := make(map[string]bool)
+	for _, v := range result.SupportedVersions {
+		supports[v] = true
+	}
+
+	grade := GradeA
+	downgrade := func(to string) {
+		if to > grade {
+			grade = to
+		}
+	}
+
+	for _, version := range []uint16{tls.VersionTLS10, tls.VersionTLS11} {
+		if supports[tls.VersionName(version)] {
+			issues = append(issues, fmt.Sprintf("仍支持已废弃的 %s", tls.VersionName(version)))
+			downgrade(GradeB)
+		}
+	}
+
+	var insecure, noForwardSecrecy []string
+	for _, suite := range result.CipherSuites {
+		if suite.Insecure {
+			insecure = append(insecure, suite.Name)
+		} else if !suite.ForwardSecrecy {
+			noForwardSecrecy = append(noForwardSecrecy, suite.Name)
+		}
+	}
+	if len(noForwardSecrecy) > 0 {
+		issues = append(issues, fmt.Sprintf("接受不具备前向保密的加密套件: %s", strings.Join(dedupStrings(noForwardSecrecy), ", ")))
+		downgrade(GradeB)
+	}
+	if len(insecure) > 0 {
+		issues = append(issues, fmt.Sprintf("接受弱加密套件: %s", strings.Join(dedupStrings(insecure), ", ")))
+		downgrade(GradeC)
+	}
+
+	if !supports[tls.VersionName(tls.VersionTLS12)] && !supports[tls.VersionName(tls.VersionTLS13)] {
+		issues = append(issues, "不支持 TLS 1.2 及以上版本")
+		downgrade(GradeF)
+	}
+
+	return grade, issues
+}
+
+// dedupStrings 去重并保持顺序
+func dedupStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
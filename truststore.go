@@ -0,0 +1,231 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// TrustStore 自定义信任根（私有CA证书包）
+type TrustStore struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	IsGlobal    bool     `json:"isGlobal"`  // 是否对所有查询生效
+	CertCount   int      `json:"certCount"` // 包含的CA证书数量
+	Subjects    []string `json:"subjects"`  // CA证书主体列表
+	CreatedTime string   `json:"createdTime"`
+}
+
+// TrustStoresResult 信任根列表查询结果
+type TrustStoresResult struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Total   int          `json:"total"`
+	Stores  []TrustStore `json:"stores"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// parseCAPEM 解析PEM格式的CA证书包，至少包含一个证书
+func parseCAPEM(pemText string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(pemText)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("解析证书失败: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("未找到PEM格式的证书（需包含 -----BEGIN CERTIFICATE----- 块）")
+	}
+	return certs, nil
+}
+
+// AddTrustStore 添加自定义信任根（PEM文件内容或粘贴的PEM文本）
+func (a *App) AddTrustStore(name, pemText string) QueryResult {
+	if a.db == nil {
+		return QueryResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return QueryResult{
+			Success: false,
+			Error:   "名称不能为空",
+		}
+	}
+
+	certs, err := parseCAPEM(pemText)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
+	_, err = a.db.Exec("INSERT INTO trust_stores (name, pem) VALUES (?, ?)", name, pemText)
+	if err != nil {
+		return QueryResult{
+			Success: false,
+			Error:   fmt.Sprintf("添加失败: %v", err),
+		}
+	}
+
+	return QueryResult{
+		Success: true,
+		Message: fmt.Sprintf("添加信任根成功，共 %d 个CA证书", len(certs)),
+	}
+}
+
+// GetTrustStores 获取自定义信任根列表
+func (a *App) GetTrustStores() TrustStoresResult {
+	if a.db == nil {
+		return TrustStoresResult{
+			Success: false,
+			Error:   "数据库未初始化",
+		}
+	}
+
+	rows, err := a.db.Query(`
+	SELECT id, name, pem, is_global,
+	       strftime('%Y-%m-%d %H:%M:%S', created_time) as created_time
+	FROM trust_stores
+	ORDER BY created_time DESC
+	`)
+	if err != nil {
+		return TrustStoresResult{
+			Success: false,
+			Error:   fmt.Sprintf("查询失败: %v", err),
+		}
+	}
+	defer rows.Close()
+
+	var stores []TrustStore
+	for rows.Next() {
+		var store TrustStore
+		var pemText string
+		if err := rows.Scan(&store.ID, &store.Name, &pemText, &store.IsGlobal, &store.CreatedTime); err != nil {
+			continue
+		}
+
+		certs, _ := parseCAPEM(pemText)
+		store.CertCount = len(certs)
+		for _, cert := range certs {
+			store.Subjects = append(store.Subjects, cert.Subject.String())
+		}
+		stores = append(stores, store)
+	}
+
+	return TrustStoresResult{
+		Success: true,
+		Message: fmt.Sprintf("查询到 %d 个信任根", len(stores)),
+		Total:   len(stores),
+		Stores:  stores,
+	}
+}
+
+// RemoveTrustStore 删除自定义信任根，并解除关注域名上的引用
+func (a *App) RemoveTrustStore(id int64) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	if _, err := a.db.Exec("UPDATE watched_domains SET trust_store_id = 0 WHERE trust_store_id = ?", id); err != nil {
+		return fmt.Errorf("解除关注域名引用失败: %v", err)
+	}
+
+	_, err := a.db.Exec("DELETE FROM trust_stores WHERE id = ?", id)
+	return err
+}
+
+// SetTrustStoreGlobal 设置信任根是否全局生效（全局信任根用于所有查询）
+func (a *App) SetTrustStoreGlobal(id int64, global bool) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	_, err := a.db.Exec("UPDATE trust_stores SET is_global = ? WHERE id = ?", global, id)
+	if err != nil {
+		return fmt.Errorf("更新信任根失败: %v", err)
+	}
+
+	fmt.Printf("✅ 更新信任根成功: ID=%d, 全局=%v\n", id, global)
+	return nil
+}
+
+// UpdateWatchedDomainTrustStore 为关注域名指定信任根（0表示仅使用系统根证书和全局信任根）
+func (a *App) UpdateWatchedDomainTrustStore(id int64, trustStoreID int64) error {
+	if a.db == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+
+	if trustStoreID != 0 {
+		var count int
+		if err := a.db.QueryRow("SELECT COUNT(*) FROM trust_stores WHERE id = ?", trustStoreID).Scan(&count); err != nil {
+			return fmt.Errorf("查询信任根失败: %v", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("信任根不存在")
+		}
+	}
+
+	_, err := a.db.Exec("UPDATE watched_domains SET trust_store_id = ? WHERE id = ?", trustStoreID, id)
+	if err != nil {
+		return fmt.Errorf("更新信任根失败: %v", err)
+	}
+
+	fmt.Printf("✅ 更新关注域名信任根成功: ID=%d, 信任根=%d\n", id, trustStoreID)
+	return nil
+}
+
+// trustRoots 构建校验证书链使用的信任根：系统根证书 + 全局信任根 + 指定信任根
+// 没有任何自定义信任根时返回nil，表示直接使用系统根证书
+func (a *App) trustRoots(trustStoreID int64) (*x509.CertPool, error) {
+	if a.db == nil {
+		return nil, nil
+	}
+
+	rows, err := a.db.Query("SELECT pem FROM trust_stores WHERE is_global = 1 OR id = ?", trustStoreID)
+	if err != nil {
+		return nil, fmt.Errorf("查询信任根失败: %v", err)
+	}
+	defer rows.Close()
+
+	var pems []string
+	for rows.Next() {
+		var pemText string
+		if err := rows.Scan(&pemText); err != nil {
+			return nil, err
+		}
+		pems = append(pems, pemText)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(pems) == 0 {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	for _, pemText := range pems {
+		pool.AppendCertsFromPEM([]byte(pemText))
+	}
+	return pool, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// 证书状态
+const (
+	StatusSafe      = "safe"      // 安全
+	StatusWarning   = "warning"   // 30天内过期
+	StatusDanger    = "danger"    // 7天内过期
+	StatusExpired   = "expired"   // 已过期
+	StatusUntrusted = "untrusted" // 证书链不受信任（自签名、缺少中间证书等）
+	StatusMismatch  = "mismatch"  // 证书与主机名不匹配
+	StatusRevoked   = "revoked"   // 证书已被吊销
+)
+
+// statusForDays 根据剩余天数计算证书状态
+func statusForDays(daysRemaining int) string {
+	if daysRemaining < 0 {
+		return StatusExpired
+	} else if daysRemaining <= 7 {
+		return StatusDanger
+	} else if daysRemaining <= 30 {
+		return StatusWarning
+	}
+	return StatusSafe
+}
+
+// statusSeverity 状态的严重程度，数值越大越严重
+func statusSeverity(status string) int {
+	switch status {
+	case StatusRevoked:
+		return 6
+	case StatusExpired:
+		return 5
+	case StatusMismatch:
+		return 4
+	case StatusUntrusted:
+		return 3
+	case StatusDanger:
+		return 2
+	case StatusWarning:
+		return 1
+	}
+	return 0
+}
+
+// isVerifyFailure 判断状态是否为证书校验失败（含已吊销）
+func isVerifyFailure(status string) bool {
+	return status == StatusUntrusted || status == StatusMismatch || status == StatusRevoked
+}
+
+// verifyResult 证书校验结果
+type verifyResult struct {
+	ChainValid    bool
+	HostnameMatch bool
+	Errors        []string
+}
+
+// verifyCertificate 独立于握手校验服务器证书：证书链对照信任根（roots为nil时使用系统根证书），
+// 主机名对照叶子证书的SAN
+func verifyCertificate(certs []*x509.Certificate, hostname string, roots *x509.CertPool, now time.Time) verifyResult {
+	var result verifyResult
+	if len(certs) == 0 {
+		result.Errors = append(result.Errors, "服务器未返回证书")
+		return result
+	}
+
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("证书链校验失败: %s", describeVerifyError(err)))
+	} else {
+		result.ChainValid = true
+	}
+
+	if err := leaf.VerifyHostname(hostname); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("主机名校验失败: %v", err))
+	} else {
+		result.HostnameMatch = true
+	}
+
+	return result
+}
+
+// describeVerifyError 将x509校验错误转换为易读的说明
+func describeVerifyError(err error) string {
+	switch e := err.(type) {
+	case x509.UnknownAuthorityError:
+		if e.Cert != nil && e.Cert.Subject.String() == e.Cert.Issuer.String() {
+			return "自签名证书"
+		}
+		return "未知的颁发机构（可能缺少中间证书或使用私有CA）"
+	case x509.CertificateInvalidError:
+		switch e.Reason {
+		case x509.Expired:
+			return "证书已过期或尚未生效"
+		case x509.NotAuthorizedToSign:
+			return "证书链中存在无权签发的证书"
+		case x509.IncompatibleUsage:
+			return "证书用途不适用于服务器认证"
+		}
+		return e.Error()
+	case x509.HostnameError:
+		return e.Error()
+	}
+	return err.Error()
+}